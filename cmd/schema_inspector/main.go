@@ -1,96 +1,322 @@
+// Command schema_inspector inspects CSV and Parquet files (or glob patterns of them)
+//
+// Usage:
+//
+//	schema_inspector schema data.parquet
+//	schema_inspector head -n 20 -format json "data/*.csv"
+//	schema_inspector count data.parquet
+//	schema_inspector metadata data.parquet
+//	schema_inspector describe -format csv data.parquet
+//	schema_inspector query data.parquet "SELECT COUNT(*) FROM df"
 package main
 
 import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
 	"os"
-	"time"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/isesword/firn/polars"
 )
 
+// Exit codes
+const (
+	exitOK    = 0 // Command succeeded
+	exitError = 1 // Reading or processing the input failed
+	exitUsage = 2 // Invalid command line
+)
+
+const usage = `Usage: schema_inspector <command> [flags] <path> [sql]
+
+Commands:
+  schema    Print column names and data types
+  head      Print the first rows (-n rows, default 10)
+  count     Print the total number of rows (read from the footer for a single Parquet file)
+  metadata  Print Parquet row groups, column chunks and key-value metadata
+  describe  Print summary statistics for every column
+  query     Run a SQL query; the input is registered as table "df"
+
+Flags:
+  -format   Output format: table, json or csv (default table)
+  -input    Input format: auto, csv or parquet (default auto, detected from extension)
+
+Paths may be glob patterns such as "data/*.parquet".
+`
+
 func main() {
-	if len(os.Args) < 2 {
-		log.Fatal("Usage: go run main.go <parquet_file_path>")
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return exitUsage
 	}
 
-	parquetFile := os.Args[1]
-	fmt.Printf("🔍 Inspecting Parquet file: %s\n", parquetFile)
-	fmt.Println("================================================================================")
+	command, args := args[0], args[1:]
+	switch command {
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return exitOK
+	case "schema", "head", "count", "metadata", "describe", "query":
+	default:
+		fmt.Fprintf(stderr, "schema_inspector: unknown command %q\n\n%s", command, usage)
+		return exitUsage
+	}
 
-	// Read just the first few rows to inspect schema
-	fmt.Println("📊 Reading sample data to inspect schema...")
-	start := time.Now()
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	format := flags.String("format", "table", "output format: table, json or csv")
+	input := flags.String("input", "auto", "input format: auto, csv or parquet")
+	rows := 10
+	if command == "head" {
+		flags.IntVar(&rows, "n", 10, "number of rows to print")
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
 
-	df := polars.ReadParquetWithOptions(parquetFile, polars.ParquetOptions{
-		NRows:    10, // Just read first 10 rows for schema inspection
-		Parallel: true,
-	})
+	wantArgs := 1
+	if command == "query" {
+		wantArgs = 2
+	}
+	if flags.NArg() != wantArgs {
+		fmt.Fprintf(stderr, "schema_inspector: %s expects %d argument(s), got %d\n\n%s",
+			command, wantArgs, flags.NArg(), usage)
+		return exitUsage
+	}
 
-	result, err := df.Collect()
-	if err != nil {
-		log.Fatalf("Error reading Parquet file: %v", err)
+	switch *format {
+	case "table", "json", "csv":
+	default:
+		fmt.Fprintf(stderr, "schema_inspector: unknown output format %q\n", *format)
+		return exitUsage
 	}
-	defer result.Release()
 
-	elapsed := time.Since(start)
-	fmt.Printf("⏱️  Schema read completed in: %v\n\n", elapsed)
+	if command == "head" && rows <= 0 {
+		fmt.Fprintf(stderr, "schema_inspector: -n must be positive, got %d\n", rows)
+		return exitUsage
+	}
 
-	// Display basic info
-	height, err := result.Height()
+	path := flags.Arg(0)
+	inputFormat, err := detectInput(path, *input)
 	if err != nil {
-		log.Fatalf("Error getting height: %v", err)
+		fmt.Fprintf(stderr, "schema_inspector: %v\n", err)
+		return exitUsage
 	}
 
-	fmt.Printf("📏 Sample dimensions: %d rows\n", height)
-	fmt.Println()
+	// Footer-only commands never scan data pages
+	if inputFormat == "parquet" && !isGlob(path) && (command == "count" || command == "metadata") {
+		if err := printParquetMetadata(path, command == "count", *format, stdout); err != nil {
+			fmt.Fprintf(stderr, "schema_inspector: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+	if command == "metadata" {
+		fmt.Fprintln(stderr, "schema_inspector: metadata requires a single Parquet file")
+		return exitUsage
+	}
 
-	// Display the sample data
-	fmt.Println("📋 Sample data (first 10 rows):")
-	fmt.Println(result.String())
-	fmt.Println()
+	df := openInput(path, inputFormat)
 
-	// Now get the full row count (this might take a while for 11GB file)
-	fmt.Println("🔢 Counting total rows in dataset...")
-	start = time.Now()
+	switch command {
+	case "schema":
+		// A single row is enough to resolve the schema
+		err = printSchema(df.Limit(1), *format, stdout)
+	case "head":
+		err = printFrame(df.Limit(rows), *format, stdout)
+	case "count":
+		err = printFrame(df.Count(), *format, stdout)
+	case "describe":
+		err = printFrame(df.Describe(), *format, stdout)
+	case "query":
+		err = printFrame(df.Query(flags.Arg(1)), *format, stdout)
+	}
 
-	countDf := polars.ReadParquet(parquetFile)
-	countResult, err := countDf.Count().Collect()
 	if err != nil {
-		log.Fatalf("Error counting rows: %v", err)
+		fmt.Fprintf(stderr, "schema_inspector: %v\n", err)
+		return exitError
 	}
-	defer countResult.Release()
+	return exitOK
+}
 
-	elapsed = time.Since(start)
-	fmt.Printf("⏱️  Row count completed in: %v\n", elapsed)
-	fmt.Println("📊 Total row count:")
-	fmt.Println(countResult.String())
-	fmt.Println()
+// detectInput resolves the input format, detecting it from the extension when input is "auto"
+func detectInput(path, input string) (string, error) {
+	switch input {
+	case "csv", "parquet":
+		return input, nil
+	case "auto":
+	default:
+		return "", fmt.Errorf("unknown input format %q", input)
+	}
 
-	// Get column names and basic statistics
-	fmt.Println("📈 Analyzing column characteristics...")
-	start = time.Now()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".tsv", ".txt":
+		return "csv", nil
+	case ".parquet", ".pq":
+		return "parquet", nil
+	default:
+		return "", fmt.Errorf("cannot detect input format of %q, use -input csv or -input parquet", path)
+	}
+}
 
-	// Try to get some basic stats on a sample
-	statsDf := polars.ReadParquetWithOptions(parquetFile, polars.ParquetOptions{
-		NRows:    100000, // Sample 100k rows for stats
+// isGlob reports whether path contains glob metacharacters
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[")
+}
+
+// openInput creates a lazy DataFrame for path; glob patterns are expanded
+func openInput(path, inputFormat string) *polars.DataFrame {
+	if inputFormat == "csv" {
+		return polars.ReadCSV(path)
+	}
+	return polars.ReadParquetWithOptions(path, polars.ParquetOptions{
 		Parallel: true,
 	})
+}
 
-	// Try to describe the data (this will show data types and basic stats)
-	statsResult, err := statsDf.Collect()
+// printParquetMetadata writes the footer row count (countOnly) or the full footer metadata to w
+func printParquetMetadata(path string, countOnly bool, format string, w io.Writer) error {
+	metadata, err := polars.ReadParquetMetadata(path)
 	if err != nil {
-		log.Fatalf("Error reading sample for stats: %v", err)
+		return err
+	}
+
+	if countOnly {
+		return printFrame(polars.FromColumns(map[string][]any{
+			"count": {metadata.NumRows},
+		}), format, w)
 	}
-	defer statsResult.Release()
 
-	elapsed = time.Since(start)
-	fmt.Printf("⏱️  Statistics analysis completed in: %v\n", elapsed)
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(metadata)
+	case "csv":
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{"row_group", "column", "physical_type", "compression",
+			"compressed_size", "uncompressed_size", "num_values", "null_count", "min", "max"})
+		for i, rg := range metadata.RowGroups {
+			for _, column := range rg.Columns {
+				_ = writer.Write([]string{strconv.Itoa(i), column.Path, column.PhysicalType, column.Compression,
+					strconv.FormatInt(column.CompressedSize, 10), strconv.FormatInt(column.UncompressedSize, 10),
+					strconv.FormatInt(column.NumValues, 10), optional(column.NullCount), optional(column.Min),
+					optional(column.Max)})
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		fmt.Fprintf(w, "rows: %d\nrow groups: %d\nversion: %d\ncreated by: %s\n",
+			metadata.NumRows, metadata.NumRowGroups(), metadata.Version, metadata.CreatedBy)
+		if len(metadata.KeyValueMetadata) > 0 {
+			keys := make([]string, 0, len(metadata.KeyValueMetadata))
+			for key := range metadata.KeyValueMetadata {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			fmt.Fprintln(w, "key-value metadata:")
+			for _, key := range keys {
+				fmt.Fprintf(w, "  %s: %d bytes\n", key, len(metadata.KeyValueMetadata[key]))
+			}
+		}
+		fmt.Fprintln(w)
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "ROW GROUP\tCOLUMN\tTYPE\tCOMPRESSION\tCOMPRESSED\tUNCOMPRESSED\tVALUES\tNULLS\tMIN\tMAX")
+		for i, rg := range metadata.RowGroups {
+			for _, column := range rg.Columns {
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%d\t%d\t%d\t%s\t%s\t%s\n", i, column.Path,
+					column.PhysicalType, column.Compression, column.CompressedSize, column.UncompressedSize,
+					column.NumValues, optional(column.NullCount), optional(column.Min), optional(column.Max))
+			}
+		}
+		return tw.Flush()
+	}
+}
 
-	statsHeight, _ := statsResult.Height()
-	fmt.Printf("📊 Statistics sample: %d rows\n", statsHeight)
-	fmt.Println()
+// optional formats a possibly missing statistic, using an empty string for nil
+func optional[T any](v *T) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(*v)
+}
 
-	fmt.Println("✅ Schema inspection completed!")
-	fmt.Println("💡 Use this information to design appropriate benchmark queries.")
+// printFrame collects df and writes it to w in the requested format
+func printFrame(df *polars.DataFrame, format string, w io.Writer) error {
+	result, err := df.Collect()
+	if err != nil {
+		return err
+	}
+	defer result.Release()
+
+	var out string
+	switch format {
+	case "json":
+		out, err = result.ToJSON()
+	case "csv":
+		out, err = result.ToCsv()
+	default:
+		out = result.String()
+	}
+	if err != nil {
+		return err
+	}
+
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+// printSchema collects df and writes its column names and data types to w
+func printSchema(df *polars.DataFrame, format string, w io.Writer) error {
+	result, err := df.Collect()
+	if err != nil {
+		return err
+	}
+	defer result.Release()
+
+	fields, err := result.Schema()
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(fields)
+	case "csv":
+		writer := csv.NewWriter(w)
+		_ = writer.Write([]string{"name", "dtype"})
+		for _, field := range fields {
+			_ = writer.Write([]string{field.Name, field.DType})
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "NAME\tDTYPE")
+		for _, field := range fields {
+			fmt.Fprintf(tw, "%s\t%s\n", field.Name, field.DType)
+		}
+		return tw.Flush()
+	}
 }
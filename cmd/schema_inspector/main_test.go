@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/isesword/firn/polars"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	smallCSV    = "../../testdata/small.csv"
+	fortuneFile = "../../testdata/fortune1000_2024.parquet"
+)
+
+// runCommand runs the CLI with args and returns its exit code, stdout and stderr
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	fortune, err := polars.ReadParquetMetadata(fortuneFile)
+	require.NoError(t, err)
+	fortuneRows := strconv.FormatInt(fortune.NumRows, 10)
+
+	t.Run("Schema", func(t *testing.T) {
+		code, out, errOut := runCommand("schema", smallCSV)
+		require.Equal(t, exitOK, code, errOut)
+		require.Equal(t, "NAME   DTYPE\nid     i64\nvalue  i64\n", out)
+
+		code, out, errOut = runCommand("schema", "-format", "csv", smallCSV)
+		require.Equal(t, exitOK, code, errOut)
+		require.Equal(t, "name,dtype\nid,i64\nvalue,i64\n", out)
+
+		code, out, errOut = runCommand("schema", "-format", "json", smallCSV)
+		require.Equal(t, exitOK, code, errOut)
+		var fields []map[string]string
+		require.NoError(t, json.Unmarshal([]byte(out), &fields))
+		require.Equal(t, []map[string]string{
+			{"name": "id", "dtype": "i64"},
+			{"name": "value", "dtype": "i64"},
+		}, fields)
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		code, out, errOut := runCommand("head", "-n", "2", "-format", "csv", smallCSV)
+		require.Equal(t, exitOK, code, errOut)
+		require.Equal(t, "id,value\n1,100\n2,200\n", out)
+
+		code, out, errOut = runCommand("head", "-n", "1", "-format", "json", smallCSV)
+		require.Equal(t, exitOK, code, errOut)
+		require.Equal(t, `[{"id":1,"value":100}]`+"\n", out)
+
+		code, out, errOut = runCommand("head", "-n", "1", smallCSV)
+		require.Equal(t, exitOK, code, errOut)
+		require.Contains(t, out, "shape: (1, 2)")
+	})
+
+	t.Run("Count", func(t *testing.T) {
+		code, out, errOut := runCommand("count", "-format", "csv", smallCSV)
+		require.Equal(t, exitOK, code, errOut)
+		require.Contains(t, out, "3")
+
+		// A single Parquet file is counted from its footer
+		code, out, errOut = runCommand("count", "-format", "csv", fortuneFile)
+		require.Equal(t, exitOK, code, errOut)
+		require.Equal(t, "count\n"+fortuneRows+"\n", out)
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		code, out, errOut := runCommand("metadata", fortuneFile)
+		require.Equal(t, exitOK, code, errOut)
+		require.Contains(t, out, "rows: "+fortuneRows+"\n")
+		require.Contains(t, out, "ROW GROUP")
+
+		code, out, errOut = runCommand("metadata", "-format", "json", fortuneFile)
+		require.Equal(t, exitOK, code, errOut)
+		var metadata map[string]any
+		require.NoError(t, json.Unmarshal([]byte(out), &metadata))
+		require.Equal(t, float64(fortune.NumRows), metadata["num_rows"])
+
+		code, out, errOut = runCommand("metadata", "-format", "csv", fortuneFile)
+		require.Equal(t, exitOK, code, errOut)
+		require.True(t, strings.HasPrefix(out, "row_group,column,physical_type,"))
+	})
+
+	t.Run("Describe", func(t *testing.T) {
+		code, out, errOut := runCommand("describe", "-format", "csv", smallCSV)
+		require.Equal(t, exitOK, code, errOut)
+		require.Contains(t, out, "mean")
+		require.Contains(t, out, "200.0")
+	})
+
+	t.Run("Query", func(t *testing.T) {
+		code, out, errOut := runCommand("query", "-format", "csv", smallCSV,
+			"SELECT id FROM df WHERE value > 150 ORDER BY id")
+		require.Equal(t, exitOK, code, errOut)
+		require.Equal(t, "id\n2\n3\n", out)
+	})
+
+	t.Run("GlobInput", func(t *testing.T) {
+		code, out, errOut := runCommand("count", "-format", "csv", "../../testdata/fortune*.parquet")
+		require.Equal(t, exitOK, code, errOut)
+		require.Contains(t, out, fortuneRows)
+	})
+
+	t.Run("Help", func(t *testing.T) {
+		code, out, _ := runCommand("help")
+		require.Equal(t, exitOK, code)
+		require.Contains(t, out, "Usage: schema_inspector")
+	})
+
+	t.Run("UsageErrors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{},
+			{"bogus", smallCSV},
+			{"schema"},
+			{"query", smallCSV},
+			{"schema", "-format", "xml", smallCSV},
+			{"schema", "-input", "avro", smallCSV},
+			{"schema", "data.unknown"},
+			{"head", "-n", "0", smallCSV},
+			{"head", "-undefined", smallCSV},
+			{"metadata", smallCSV},
+		} {
+			code, _, errOut := runCommand(args...)
+			require.Equal(t, exitUsage, code, "args %q", args)
+			require.NotEmpty(t, errOut, "args %q", args)
+		}
+	})
+
+	t.Run("ProcessingErrors", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"schema", "../../testdata/missing.csv"},
+			{"metadata", "../../testdata/missing.parquet"},
+			{"query", smallCSV, "SELECT missing FROM df"},
+		} {
+			code, _, errOut := runCommand(args...)
+			require.Equal(t, exitError, code, "args %q", args)
+			require.Contains(t, errOut, "schema_inspector: ", "args %q", args)
+		}
+	})
+}
@@ -0,0 +1,88 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"errors"
+	"net/url"
+)
+
+// CloudOptions configures access to S3-compatible object storage for s3:// paths
+// Empty fields fall back to the AWS environment (AWS_REGION, AWS_ACCESS_KEY_ID, ...)
+// Example for a local MinIO:
+//
+//	df := polars.ReadParquetWithOptions("s3://lake/events/*.parquet", polars.ParquetOptions{
+//	    Cloud: &polars.CloudOptions{
+//	        Endpoint:    "http://localhost:9000",
+//	        Region:      "us-east-1",
+//	        Credentials: polars.CloudCredentials{AccessKeyID: "minioadmin", SecretAccessKey: "minioadmin"},
+//	        AllowHTTP:   true,
+//	    },
+//	})
+type CloudOptions struct {
+	Endpoint    string           // Custom endpoint URL ("" = AWS)
+	Region      string           // Bucket region ("" = from environment)
+	Credentials CloudCredentials // Static credentials (zero value = default credential chain)
+	AllowHTTP   bool             // Permit plain-HTTP endpoints such as a local MinIO
+}
+
+// CloudCredentials holds static S3 credentials
+type CloudCredentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string // Only for temporary credentials
+}
+
+// validate rejects settings that would only fail once the scan starts
+func (o *CloudOptions) validate() error {
+	if o == nil {
+		return nil
+	}
+	if (o.Credentials.AccessKeyID == "") != (o.Credentials.SecretAccessKey == "") {
+		return errors.New("cloud credentials need both AccessKeyID and SecretAccessKey")
+	}
+	if o.Endpoint == "" {
+		return nil
+	}
+	endpoint, err := url.Parse(o.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return errors.New("cloud endpoint must be an absolute URL such as http://localhost:9000")
+	}
+	if endpoint.Scheme == "http" && !o.AllowHTTP {
+		return errors.New("cloud endpoint uses http:// but AllowHTTP is false")
+	}
+	return nil
+}
+
+// clone copies o, so a caller changing its CloudOptions after building a scan cannot
+// bypass validate or the credential check of MarshalPlan
+func (o *CloudOptions) clone() *CloudOptions {
+	if o == nil {
+		return nil
+	}
+	copied := *o
+	return &copied
+}
+
+// hasCredentials reports whether o carries static credentials
+func (o *CloudOptions) hasCredentials() bool {
+	return o != nil && o.Credentials != CloudCredentials{}
+}
+
+// makeCloudArgs converts CloudOptions to the C argument struct (nil when not set)
+// Must be called inside an operation's args closure so the referenced memory stays alive
+func makeCloudArgs(o *CloudOptions) *C.CloudArgs {
+	if o == nil {
+		return nil
+	}
+	return &C.CloudArgs{
+		endpoint:          makeRawStr(o.Endpoint),
+		region:            makeRawStr(o.Region),
+		access_key_id:     makeRawStr(o.Credentials.AccessKeyID),
+		secret_access_key: makeRawStr(o.Credentials.SecretAccessKey),
+		session_token:     makeRawStr(o.Credentials.SessionToken),
+		allow_http:        C.bool(o.AllowHTTP),
+	}
+}
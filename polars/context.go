@@ -0,0 +1,96 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// executeCancellable runs the operations on a separate goroutine while watching ctx
+// When ctx is done the Rust side is signalled through a cancellation token; we still
+// wait for the call to return because Rust reads cOps and their arguments until then
+func executeCancellable(ctx context.Context, handle C.PolarsHandle, cOps []C.Operation) C.FfiResult {
+	var result C.FfiResult
+	runCancellable(ctx, func(token C.uintptr_t) {
+		result = C.execute_operations_cancellable(handle, &cOps[0], C.size_t(len(cOps)), token)
+	})
+	return result
+}
+
+// runCancellable runs call on a separate goroutine with a cancellation token that is
+// cancelled once ctx is done, and waits for call to return either way
+// Without a ctx that can be cancelled call runs directly with token 0 (none)
+func runCancellable(ctx context.Context, call func(token C.uintptr_t)) {
+	if ctx.Done() == nil {
+		call(0)
+		return
+	}
+
+	token := C.cancel_token_new()
+	defer C.cancel_token_free(token)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		call(token)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		C.cancel_token_cancel(token)
+		<-done
+	}
+}
+
+// encodeCancellable runs a dataframe_to_* buffer encoder under runCancellable
+// It returns the encoded bytes, which the caller frees with C.free_buffer, or ctx.Err()
+// when the encoder stopped because ctx was done
+func encodeCancellable(ctx context.Context, message string,
+	encode func(token C.uintptr_t, length *C.size_t, errorCode *C.int, errorMessage **C.char) *C.uint8_t,
+) (*C.uint8_t, C.size_t, error) {
+	var length C.size_t
+	var errorCode C.int
+	var errorMessage *C.char
+	var buffer *C.uint8_t
+	runCancellable(ctx, func(token C.uintptr_t) {
+		buffer = encode(token, &length, &errorCode, &errorMessage)
+	})
+	if buffer != nil {
+		return buffer, length, nil
+	}
+	if errorCode == codeCancelled && ctx.Err() != nil {
+		C.free_string(errorMessage)
+		return nil, 0, ctx.Err()
+	}
+	return nil, 0, ffiError(errorCode, errorMessage, message)
+}
+
+// writeChunkSize bounds how much is written to w between ctx checks
+const writeChunkSize = 1 << 20
+
+// writeContext copies data to w, in chunks with ctx checks in between when ctx can be cancelled
+func writeContext(ctx context.Context, w io.Writer, data []byte, name string) error {
+	if ctx.Done() == nil {
+		if _, err := w.Write(data); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+
+	for len(data) > 0 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		n := min(len(data), writeChunkSize)
+		if _, err := w.Write(data[:n]); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		data = data[n:]
+	}
+	return nil
+}
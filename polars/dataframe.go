@@ -5,8 +5,13 @@ package polars
 */
 import "C"
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"runtime"
+	"slices"
+	"sync"
 	"unsafe"
 )
 
@@ -26,6 +31,11 @@ type Operation struct {
 	opcode uint32                // OpCode for the operation
 	args   func() unsafe.Pointer // Lazy args allocation via closure (keeps references alive naturally)
 	err    error                 // Error associated with this operation (if any)
+	site   *callSite             // Builder call that queued this operation, for error attribution
+	refs   []*handleRef          // Handle references its sub-plans hold; released once it has run
+	// secret marks operations whose arguments hold cloud credentials, directly or in a
+	// sub-plan; MarshalPlan refuses to write those out
+	secret bool
 }
 
 // Helper functions for creating error operations
@@ -50,35 +60,29 @@ func errOpf(format string, args ...interface{}) Operation {
 
 // appendErrOp appends an error operation to a DataFrame and returns it
 func (df *DataFrame) appendErrOp(message string) *DataFrame {
-	df.operations = append(df.operations, errOp(message))
+	df.operations = append(df.operations, withCallSite(errOp(message)))
 	return df
 }
 
 // appendErrOpf appends a formatted error operation to a DataFrame and returns it
 func (df *DataFrame) appendErrOpf(format string, args ...interface{}) *DataFrame {
-	df.operations = append(df.operations, errOpf(format, args...))
+	df.operations = append(df.operations, withCallSite(errOpf(format, args...)))
 	return df
 }
 
 // DataFrame represents a Polars DataFrame with lazy operations
+// Building and collecting one DataFrame is not safe for concurrent use, but a collected
+// DataFrame can be passed to Join or Concat from many goroutines, even while another
+// goroutine calls Release on it.
 type DataFrame struct {
+	mu         sync.Mutex     // Guards handle and ref against concurrent Release and embedding
 	handle     C.PolarsHandle // Handle with context type information
+	ref        *handleRef     // This DataFrame's reference to handle; nil before the first Collect
 	operations []Operation    // Pending operations to execute
 }
 
-// Error represents a Polars operation error
-type Error struct {
-	Code    int
-	Message string
-	Frame   int
-}
-
-func (e *Error) Error() string {
-	if e.Frame > 0 {
-		return fmt.Sprintf("polars error %d at operation %d: %s", e.Code, e.Frame, e.Message)
-	}
-	return fmt.Sprintf("polars error %d: %s", e.Code, e.Message)
-}
+// Methods that pass df.handle.handle to Rust call runtime.KeepAlive(df) after the call:
+// once df is unreachable the cleanup of ref may release the handle, even mid-call.
 
 // NewDataFrame creates a new empty DataFrame
 func NewDataFrame() *DataFrame {
@@ -89,60 +93,235 @@ func NewDataFrame() *DataFrame {
 
 	return &DataFrame{
 		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
-		operations: []Operation{op},
+		operations: []Operation{withCallSite(op)},
 	}
 }
 
-// ReadCSV creates a DataFrame from a CSV file with default options
+// CSVEncoding selects how CSV bytes are decoded
+type CSVEncoding uint8
+
+const (
+	CSVEncodingUTF8      CSVEncoding = 0 // Invalid UTF-8 is an error
+	CSVEncodingUTF8Lossy CSVEncoding = 1 // Invalid UTF-8 is replaced with U+FFFD
+)
+
+// CSVOptions configures CSV reading options
+// Zero values fall back to Polars defaults, except HasHeader and WithGlob: left false they
+// read the first line as data and take the path literally, so start from
+// DefaultCSVOptions() or set both explicitly
+type CSVOptions struct {
+	HasHeader         bool                // Whether CSV has header row (false = first line is data)
+	WithGlob          bool                // Whether to expand glob patterns (false = literal path)
+	Separator         byte                // Field separator (0 = ',')
+	QuoteChar         byte                // Quote character (0 = '"')
+	CommentPrefix     string              // Skip lines starting with this prefix ("" = none)
+	SkipRows          int                 // Rows to skip before the header
+	NRows             int                 // Optional row limit (0 = all rows)
+	SchemaOverrides   map[string]DataType // Column dtypes that bypass inference
+	NullValues        []string            // Strings read as null in every column
+	InferSchemaLength int                 // Rows used for inference (0 = 100 rows, -1 = whole file)
+	TryParseDates     bool                // Parse date/datetime-looking strings
+	IgnoreErrors      bool                // Turn unparsable values into nulls instead of failing
+	Encoding          CSVEncoding         // Text encoding (default UTF-8)
+	LowMemory         bool                // Reduce memory usage at the cost of speed
+	RowIndexName      string              // Adds a row index column with this name ("" = none)
+	RowIndexOffset    uint32              // First value of the row index
+	Cloud             *CloudOptions       // Object store settings for s3:// paths (nil = environment defaults)
+	IncludeFilePaths  string              // Adds a column with each row's source file ("" = none)
+}
+
+// DefaultCSVOptions returns the options used by ReadCSV
 // - has_header: true (assumes CSV has header row)
 // - with_glob: true (enables glob pattern expansion for paths like "data_*.csv")
+func DefaultCSVOptions() CSVOptions {
+	return CSVOptions{
+		HasHeader: true,
+		WithGlob:  true,
+	}
+}
+
+// ReadCSV creates a DataFrame from a CSV file with default options
 func ReadCSV(path string) *DataFrame {
-	return ReadCSVWithOptions(path, true, true)
+	return ReadCSVWithOptions(path, DefaultCSVOptions())
 }
 
 // ReadCSVWithOptions creates a DataFrame from a CSV file with configurable options
-func ReadCSVWithOptions(path string, hasHeader bool, withGlob bool) *DataFrame {
+// Example:
+//
+//	df := polars.ReadCSVWithOptions("data.tsv", polars.CSVOptions{
+//	    HasHeader:       true,
+//	    Separator:       '\t',
+//	    NullValues:      []string{"NA", ""},
+//	    SchemaOverrides: map[string]polars.DataType{"zip": polars.String},
+//	})
+func ReadCSVWithOptions(path string, options CSVOptions) *DataFrame {
+	options.Cloud = options.Cloud.clone() // Validated now, read when the plan executes
+	if err := validateCSVOptions(options); err != nil {
+		return NewDataFrame().appendErrOpf("ReadCSV: %v", err)
+	}
+	overrideNames := sortedOverrideNames(options.SchemaOverrides)
+
 	op := Operation{
 		opcode: OpReadCsv,
 		args: func() unsafe.Pointer {
-			return unsafe.Pointer(&C.ReadCsvArgs{
-				path:       makeRawStr(path), // path captured by closure
-				has_header: C.bool(hasHeader),
-				with_glob:  C.bool(withGlob),
-			})
+			args := newReadCsvArgs(path, options, overrideNames) // path captured by closure
+			return unsafe.Pointer(&args)
 		},
+		secret: options.Cloud.hasCredentials(),
 	}
 
 	return &DataFrame{
 		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
-		operations: []Operation{op},
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// validateCSVOptions rejects options that can be checked before execution
+func validateCSVOptions(options CSVOptions) error {
+	if options.SkipRows < 0 || options.NRows < 0 {
+		return fmt.Errorf("SkipRows (%d) and NRows (%d) cannot be negative", options.SkipRows, options.NRows)
+	}
+	if options.Separator != 0 && options.Separator == options.QuoteChar {
+		return fmt.Errorf("separator and quote char cannot both be %q", options.Separator)
+	}
+	if options.Encoding != CSVEncodingUTF8 && options.Encoding != CSVEncodingUTF8Lossy {
+		return fmt.Errorf("unknown encoding %d", options.Encoding)
+	}
+	if err := options.Cloud.validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// sortedOverrideNames returns the column names of overrides in sorted order
+// so the generated plan is deterministic
+func sortedOverrideNames(overrides map[string]DataType) []string {
+	overrideNames := make([]string, 0, len(overrides))
+	for name := range overrides {
+		overrideNames = append(overrideNames, name)
+	}
+	slices.Sort(overrideNames)
+	return overrideNames
+}
+
+// makeSchemaOverrides builds the C array of column dtype overrides (nil when empty)
+// Must be called inside an operation's args closure so the referenced memory stays alive
+func makeSchemaOverrides(names []string, dtypes map[string]DataType) *C.SchemaOverride {
+	if len(names) == 0 {
+		return nil
+	}
+	overrides := make([]C.SchemaOverride, len(names))
+	for i, name := range names {
+		overrides[i] = C.SchemaOverride{
+			name:  makeRawStr(name),
+			dtype: C.uint32_t(dtypes[name]),
+		}
+	}
+	return &overrides[0]
+}
+
+// newReadCsvArgs converts CSVOptions to the C argument struct
+// Must be called inside an operation's args closure so the referenced memory stays alive
+func newReadCsvArgs(path string, options CSVOptions, overrideNames []string) C.ReadCsvArgs {
+	var nullValuesPtr *C.RawStr
+	if len(options.NullValues) > 0 {
+		nullValues := make([]C.RawStr, len(options.NullValues))
+		for i, value := range options.NullValues {
+			nullValues[i] = makeRawStr(value)
+		}
+		nullValuesPtr = &nullValues[0]
+	}
+
+	return C.ReadCsvArgs{
+		path:                  makeRawStr(path),
+		has_header:            C.bool(options.HasHeader),
+		with_glob:             C.bool(options.WithGlob),
+		separator:             C.uint8_t(options.Separator),
+		quote_char:            C.uint8_t(options.QuoteChar),
+		comment_prefix:        makeRawStr(options.CommentPrefix),
+		skip_rows:             C.size_t(options.SkipRows),
+		n_rows:                C.size_t(options.NRows),
+		schema_overrides:      makeSchemaOverrides(overrideNames, options.SchemaOverrides),
+		schema_override_count: C.size_t(len(overrideNames)),
+		null_values:           nullValuesPtr,
+		null_value_count:      C.size_t(len(options.NullValues)),
+		infer_schema_length:   C.int64_t(options.InferSchemaLength),
+		try_parse_dates:       C.bool(options.TryParseDates),
+		ignore_errors:         C.bool(options.IgnoreErrors),
+		encoding:              C.uint8_t(options.Encoding),
+		low_memory:            C.bool(options.LowMemory),
+		row_index_name:        makeRawStr(options.RowIndexName),
+		row_index_offset:      C.uint32_t(options.RowIndexOffset),
+		cloud:                 makeCloudArgs(options.Cloud),
+		include_file_paths:    makeRawStr(options.IncludeFilePaths),
 	}
 }
 
 // ParquetOptions configures Parquet reading options
+// Columns, NRows, Predicate and the generated columns are all applied inside the scan
 type ParquetOptions struct {
-	Columns  []string // Optional column selection (nil = all columns)
-	NRows    int      // Optional row limit (0 = all rows)
-	Parallel bool     // Enable parallel reading
-	WithGlob bool     // Whether to expand glob patterns
+	Columns           []string  // Optional column selection (nil = all columns)
+	NRows             int       // Optional row limit, applied before Predicate (0 = all rows)
+	Parallel          bool      // Enable parallel reading
+	LowMemory         bool      // Reduce memory usage at the cost of speed
+	DisableStatistics bool      // Read every row group, even those whose min/max statistics rule out filters
+	Rechunk           bool      // Rechunk the result into contiguous memory
+	Predicate         *ExprNode // Filter pushed into the scan; may use unselected columns (nil = none)
+
+	// Deprecated: ignored. Glob patterns like "data_*.parquet" are always expanded, so
+	// ParquetOptions{} reads globs like ReadParquet does.
+	WithGlob bool
+
+	// Hive partitioning turns key=value directories (e.g. year=2024/month=01/) into
+	// columns; filters on those columns skip non-matching directories entirely
+	HivePartitioning  bool                // Always parse partitions (false = only when path is a directory)
+	HiveSchema        map[string]DataType // Partition column dtypes (nil = inferred from values)
+	TryParseHiveDates bool                // Parse date-looking partition values as dates
+
+	Cloud *CloudOptions // Object store settings for s3:// paths (nil = environment defaults)
+
+	// Generated columns, placed before the selected Columns when both are set
+	RowIndexName     string // Adds a row index column with this name ("" = none)
+	RowIndexOffset   uint32 // First value of the row index
+	IncludeFilePaths string // Adds a column with each row's source file ("" = none)
 }
 
 // ReadParquet creates a DataFrame from a Parquet file with default options
 // - columns: all columns (no selection)
 // - n_rows: all rows (no limit)
 // - parallel: true (enables parallel reading)
-// - with_glob: true (enables glob pattern expansion for paths like "data_*.parquet")
+// - statistics: enabled (row groups are pruned by later filters)
+// Glob patterns like "data_*.parquet" are expanded here and in ReadParquetWithOptions
+// whatever ParquetOptions.WithGlob says
 func ReadParquet(path string) *DataFrame {
 	return ReadParquetWithOptions(path, ParquetOptions{
 		Columns:  nil,
 		NRows:    0,
 		Parallel: true,
-		WithGlob: true,
 	})
 }
 
 // ReadParquetWithOptions creates a DataFrame from a Parquet file with configurable options
 func ReadParquetWithOptions(path string, options ParquetOptions) *DataFrame {
+	options.Cloud = options.Cloud.clone() // Validated now, read when the plan executes
+	if err := options.Cloud.validate(); err != nil {
+		return NewDataFrame().appendErrOpf("ReadParquet: %v", err)
+	}
+	if options.NRows < 0 {
+		return NewDataFrame().appendErrOpf("ReadParquet: NRows cannot be negative, got %d", options.NRows)
+	}
+	hiveNames := sortedOverrideNames(options.HiveSchema)
+
+	var predicateOps []Operation
+	if options.Predicate != nil {
+		predicateOps = slices.Collect(options.Predicate.ops)
+		for _, op := range predicateOps {
+			if op.err != nil {
+				return NewDataFrame().appendErrOpf("ReadParquet: predicate: %v", op.err)
+			}
+		}
+	}
+
 	op := Operation{
 		opcode: OpReadParquet,
 		args: func() unsafe.Pointer {
@@ -160,20 +339,94 @@ func ReadParquetWithOptions(path string, options ParquetOptions) *DataFrame {
 				columnCount = C.size_t(len(options.Columns))
 			}
 
+			var predicatePtr *C.Operation
+			if len(predicateOps) > 0 {
+				cOps, _ := toCOperations(predicateOps) // Error operations were rejected above
+				predicatePtr = &cOps[0]
+			}
+
 			return unsafe.Pointer(&C.ReadParquetArgs{
-				path:         makeRawStr(path), // path captured by closure
-				columns:      columnsPtr,
-				column_count: columnCount,
-				n_rows:       C.size_t(options.NRows),
-				parallel:     C.bool(options.Parallel),
-				with_glob:    C.bool(options.WithGlob),
+				path:                 makeRawStr(path), // path captured by closure
+				columns:              columnsPtr,
+				column_count:         columnCount,
+				n_rows:               C.size_t(options.NRows),
+				parallel:             C.bool(options.Parallel),
+				with_glob:            C.bool(options.WithGlob),
+				hive_partitioning:    C.bool(options.HivePartitioning),
+				hive_schema:          makeSchemaOverrides(hiveNames, options.HiveSchema),
+				hive_schema_count:    C.size_t(len(hiveNames)),
+				try_parse_hive_dates: C.bool(options.TryParseHiveDates),
+				cloud:                makeCloudArgs(options.Cloud),
+				row_index_name:       makeRawStr(options.RowIndexName),
+				row_index_offset:     C.uint32_t(options.RowIndexOffset),
+				include_file_paths:   makeRawStr(options.IncludeFilePaths),
+				low_memory:           C.bool(options.LowMemory),
+				use_statistics:       C.bool(!options.DisableStatistics),
+				rechunk:              C.bool(options.Rechunk),
+				predicate_ops:        predicatePtr,
+				predicate_count:      C.size_t(len(predicateOps)),
+			})
+		},
+		secret: options.Cloud.hasCredentials(),
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// NDJSONOptions configures newline-delimited JSON reading options
+// Unlike CSVOptions there is no WithGlob: the Polars NDJSON scan has no switch for it and
+// always expands glob patterns
+type NDJSONOptions struct {
+	InferSchemaLength int // Rows used for inference (0 = 100 rows, -1 = whole file)
+	BatchSize         int // Rows per parsing batch (0 = Polars default)
+	NRows             int // Optional row limit (0 = all rows)
+}
+
+// ReadNDJSON creates a lazy DataFrame from a newline-delimited JSON file (one object per line)
+// Glob patterns like "events/*.ndjson" are always expanded, so a path containing *, ? or [
+// is read as a pattern
+func ReadNDJSON(path string, options NDJSONOptions) *DataFrame {
+	if options.BatchSize < 0 || options.NRows < 0 {
+		return NewDataFrame().appendErrOpf("ReadNDJSON: BatchSize (%d) and NRows (%d) cannot be negative",
+			options.BatchSize, options.NRows)
+	}
+
+	op := Operation{
+		opcode: OpReadNdjson,
+		args: func() unsafe.Pointer {
+			return unsafe.Pointer(&C.ReadNdjsonArgs{
+				path:                makeRawStr(path), // path captured by closure
+				infer_schema_length: C.int64_t(options.InferSchemaLength),
+				batch_size:          C.size_t(options.BatchSize),
+				n_rows:              C.size_t(options.NRows),
 			})
 		},
 	}
 
 	return &DataFrame{
 		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
-		operations: []Operation{op},
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// ReadJSON creates a DataFrame from a JSON file containing an array of objects
+// JSON documents cannot be scanned lazily, so the whole file is parsed when executed
+func ReadJSON(path string) *DataFrame {
+	op := Operation{
+		opcode: OpReadJson,
+		args: func() unsafe.Pointer {
+			return unsafe.Pointer(&C.ReadJsonArgs{
+				path: makeRawStr(path), // path captured by closure
+			})
+		},
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
 	}
 }
 
@@ -183,38 +436,45 @@ func ReadParquetWithOptions(path string, options ParquetOptions) *DataFrame {
 // This is where lazy operations are executed and the DataFrame is materialized
 func (df *DataFrame) Collect() (*DataFrame, error) {
 	// Add a Collect operation to the chain
-	df.operations = append(df.operations, Operation{
+	df.operations = append(df.operations, withCallSite(Operation{
 		opcode: OpCollect,
 		args:   noArgs,
-	})
+	}))
 
-	return df.execute()
+	return df.execute(context.Background())
 }
 
-func (df *DataFrame) execute() (*DataFrame, error) {
-	if len(df.operations) == 0 {
-		return nil, errors.New("no operations to execute")
+// CollectContext is Collect with cancellation: when ctx is cancelled or its deadline
+// passes, the running query is interrupted and ctx.Err() is returned. Polars stops at
+// the next operation or plan node, so a single long-running node may finish first.
+// If ctx is already done nothing is executed and the pending operations are kept.
+func (df *DataFrame) CollectContext(ctx context.Context) (*DataFrame, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	// Store the old handle for potential cleanup
-	oldHandle := df.handle.handle
+	df.operations = append(df.operations, withCallSite(Operation{
+		opcode: OpCollect,
+		args:   noArgs,
+	}))
 
-	// Defer cleanup of operations (always runs)
-	defer func() {
-		// Clear operations slice but keep capacity for reuse
-		df.operations = df.operations[:0]
-	}()
+	return df.execute(ctx)
+}
 
-	// Convert Go operations to C operations, checking for errors
-	cOps := make([]C.Operation, len(df.operations))
-	for i, op := range df.operations {
+// toCOperations converts Go operations to C operations, returning the first error operation
+func toCOperations(operations []Operation) ([]C.Operation, error) {
+	cOps := make([]C.Operation, len(operations))
+	for i, op := range operations {
 		// Check if this operation has an error
 		if op.err != nil {
-			return nil, &Error{
-				Code:    4, // ERROR_POLARS_OPERATION
+			err := &Error{
+				Code:    codeInvalidOperation,
 				Message: op.err.Error(),
 				Frame:   i,
+				cause:   op.err,
 			}
+			op.site.attribute(err)
+			return nil, err
 		}
 
 		// Call the args function to get the actual args (lazy allocation)
@@ -228,36 +488,178 @@ func (df *DataFrame) execute() (*DataFrame, error) {
 			args:   C.uintptr_t(uintptr(argsPtr)),
 		}
 	}
+	return cOps, nil
+}
+
+// subPlan is a snapshot of another DataFrame's handle and pending operations,
+// embedded in an operation's arguments (join right side, concat inputs) and
+// executed lazily by Rust in the same FFI call as the outer plan
+type subPlan struct {
+	handle     C.PolarsHandle
+	ref        *handleRef // Own reference to an executed handle, so Release on df cannot free it
+	operations []Operation
+}
 
-	// Single FFI call with the entire operation array
-	result := C.execute_operations(
-		df.handle, // Pass the full PolarsHandle with context
-		&cOps[0],
-		C.size_t(len(cOps)),
-	)
+// asSubPlan snapshots df for embedding; df itself is left untouched
+// The snapshot holds its own references to every handle it uses; the operation
+// embedding it takes them over with refs() and releases them once executed
+func (df *DataFrame) asSubPlan() (subPlan, error) {
+	for _, op := range df.operations {
+		if op.err != nil {
+			return subPlan{}, op.err
+		}
+	}
+	handle, ref := df.retain()
+	if handle.handle == 0 && len(df.operations) == 0 {
+		return subPlan{}, errors.New("DataFrame has no data (released or never read)")
+	}
+	return subPlan{
+		handle:     handle,
+		ref:        ref,
+		operations: retainOperations(df.operations), // execute() reuses the original slice
+	}, nil
+}
 
+// retain returns df's handle with a new reference to it, or no reference before the
+// first Collect; the lock makes a concurrent Release either happen first or wait
+func (df *DataFrame) retain() (C.PolarsHandle, *handleRef) {
+	df.mu.Lock()
+	defer df.mu.Unlock()
+	if df.ref == nil {
+		return df.handle, nil
+	}
+	return df.handle, retainHandleRef(df.ref.handle)
+}
+
+// retainOperations copies ops, taking new references for the handles their sub-plans hold
+// so the copy can be executed or dropped independently of the original queue
+func retainOperations(ops []Operation) []Operation {
+	ops = slices.Clone(ops)
+	for i := range ops {
+		if len(ops[i].refs) == 0 {
+			continue
+		}
+		refs := make([]*handleRef, len(ops[i].refs))
+		for j, ref := range ops[i].refs {
+			refs[j] = retainHandleRef(ref.handle)
+		}
+		ops[i].refs = refs
+	}
+	return ops
+}
+
+// retain copies the sub-plan with new references to its handles, for embedding it again
+func (p subPlan) retain() subPlan {
+	copied := subPlan{handle: p.handle, operations: retainOperations(p.operations)}
+	if p.ref != nil {
+		copied.ref = retainHandleRef(p.ref.handle)
+	}
+	return copied
+}
+
+// refs lists the handle references the sub-plan holds, including those of nested sub-plans
+func (p subPlan) refs() []*handleRef {
+	var refs []*handleRef
+	if p.ref != nil {
+		refs = append(refs, p.ref)
+	}
+	for _, op := range p.operations {
+		refs = append(refs, op.refs...)
+	}
+	return refs
+}
+
+// secret reports whether any operation of the sub-plan holds cloud credentials
+func (p subPlan) secret() bool {
+	return slices.ContainsFunc(p.operations, func(op Operation) bool { return op.secret })
+}
+
+// toC builds the C representation of the sub-plan
+func (p subPlan) toC() C.SubPlan {
+	if len(p.operations) == 0 {
+		return C.SubPlan{handle: p.handle}
+	}
+
+	// Errors were rejected in asSubPlan
+	cOps, _ := toCOperations(p.operations)
+	return C.SubPlan{
+		handle:     p.handle,
+		operations: &cOps[0],
+		count:      C.size_t(len(cOps)),
+	}
+}
+
+// execute runs the pending operations in one FFI call
+// A ctx that can be cancelled routes the call through executeCancellable
+func (df *DataFrame) execute(ctx context.Context) (*DataFrame, error) {
+	if len(df.operations) == 0 {
+		return nil, errors.New("no operations to execute")
+	}
+
+	// Store the old handle for potential cleanup
+	oldHandle := df.handle.handle
+	oldRef := df.ref
+
+	// Defer cleanup of operations (always runs)
+	defer func() {
+		// Sub-plan handles are only needed by this call; Rust holds no pointers past it
+		for _, op := range df.operations {
+			releaseHandleRefs(op.refs)
+		}
+		// Clear operations slice but keep capacity for reuse
+		df.operations = df.operations[:0]
+	}()
+
+	// Convert Go operations to C operations, checking for errors
+	cOps, err := toCOperations(df.operations)
+	if err != nil {
+		return nil, err
+	}
+
+	var result C.FfiResult
+	if ctx.Done() == nil {
+		// Single FFI call with the entire operation array
+		result = C.execute_operations(
+			df.handle, // Pass the full PolarsHandle with context
+			&cOps[0],
+			C.size_t(len(cOps)),
+		)
+	} else {
+		result = executeCancellable(ctx, df.handle, cOps)
+	}
+
+	if result.error_code == codeCancelled && ctx.Err() != nil {
+		C.free_string(result.error_message)
+		return nil, ctx.Err()
+	}
 	if result.error_code != 0 {
 		errorMsg := C.GoString(result.error_message)
 		C.free_string(result.error_message)
-		return nil, &Error{
+		err := &Error{
 			Code:    int(result.error_code),
 			Message: errorMsg,
 			Frame:   int(result.error_frame),
 		}
+		if err.Frame < len(df.operations) {
+			df.operations[err.Frame].site.attribute(err)
+		}
+		return nil, err
 	}
 
 	// Update this DataFrame's handle to the new one
+	if result.polars_handle.handle == oldHandle {
+		return df, nil
+	}
+	df.mu.Lock()
 	df.handle = result.polars_handle
+	df.ref = newHandleRef(df.handle.handle)
+	df.mu.Unlock()
 
 	// Release the old handle if it was valid (not 0) and different from new handle
 	// This prevents memory leaks from intermediate DataFrames
-	if oldHandle != 0 && oldHandle != df.handle.handle {
-		releaseResult := C.release_dataframe(C.uintptr_t(oldHandle))
-		if releaseResult != 0 {
-			// Log the error but don't fail the operation since we got a valid new handle
-			// In production, we might want to use a proper logger here
-			_ = releaseResult // Ignore the error for now
-		}
+	if oldRef != nil {
+		// Ignore the error since we got a valid new handle
+		_ = oldRef.release()
 	}
 
 	// Return this DataFrame (now with updated handle)
@@ -268,6 +670,8 @@ func (df *DataFrame) execute() (*DataFrame, error) {
 // Strings are automatically converted to SQL expressions, ExprNodes are used as-is
 // Example: df.Select("name", "salary * 1.1 as bonus", Col("age").Alias("years"))
 func (df *DataFrame) Select(args ...any) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	exprs := toExprNodes(args...)
 
 	// Add all expression operations first
@@ -290,6 +694,8 @@ func (df *DataFrame) Select(args ...any) *DataFrame {
 
 // SelectExpr adds a select operation to the DataFrame using expressions
 func (df *DataFrame) SelectExpr(exprs ...*ExprNode) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	// Add all expression operations first
 	for _, expr := range exprs {
 		for exprOp := range expr.ops {
@@ -310,6 +716,8 @@ func (df *DataFrame) SelectExpr(exprs ...*ExprNode) *DataFrame {
 
 // Count returns a DataFrame with a single row containing the count of rows
 func (df *DataFrame) Count() *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	op := Operation{
 		opcode: OpCount,
 		args:   func() unsafe.Pointer { return unsafe.Pointer(&C.CountArgs{}) }, // Lazy allocation
@@ -319,6 +727,24 @@ func (df *DataFrame) Count() *DataFrame {
 	return df
 }
 
+// EstimatedSize returns the approximate heap size of the DataFrame's data in bytes
+// Column buffers shared with other DataFrames are counted in full. This requires the
+// DataFrame to be executed first.
+func (df *DataFrame) EstimatedSize() (int, error) {
+	if df.handle.handle == 0 {
+		return 0, errors.New("DataFrame must be executed before calling EstimatedSize()")
+	}
+
+	var errorCode C.int
+	var errorMessage *C.char
+	size := C.dataframe_estimated_size(df.handle.handle, &errorCode, &errorMessage)
+	runtime.KeepAlive(df)
+	if errorCode != 0 {
+		return 0, ffiError(errorCode, errorMessage, "failed to estimate dataframe size")
+	}
+	return int(size), nil
+}
+
 // Height returns the number of rows in the DataFrame as an integer
 // This requires the DataFrame to be executed first
 func (df *DataFrame) Height() (int, error) {
@@ -326,42 +752,64 @@ func (df *DataFrame) Height() (int, error) {
 		return 0, errors.New("DataFrame must be executed before calling Height()")
 	}
 
-	height := C.dataframe_height(df.handle.handle)
+	var errorCode C.int
+	var errorMessage *C.char
+	height := C.dataframe_height(df.handle.handle, &errorCode, &errorMessage)
+	runtime.KeepAlive(df)
+	if errorCode != 0 {
+		return 0, ffiError(errorCode, errorMessage, "failed to get dataframe height")
+	}
 	return int(height), nil
 }
 
-// Concat concatenates multiple executed DataFrames vertically (union)
-// All DataFrames must be executed before calling this function
+// Concat concatenates multiple DataFrames vertically (union)
+// Inputs may be executed or lazy; lazy inputs are embedded as sub-plans so the
+// whole union is optimized and executed in a single Collect(). Each input is snapshotted
+// with its own handle reference, so collecting or releasing it afterwards is safe.
 func Concat(dataframes ...*DataFrame) *DataFrame {
 	if len(dataframes) == 0 {
 		return NewDataFrame() // Return empty DataFrame
 	}
 
+	inputs := make([]subPlan, len(dataframes))
+	var refs []*handleRef
+	secret := false
+	for i, df := range dataframes {
+		if df == nil {
+			releaseHandleRefs(refs)
+			return NewDataFrame().appendErrOpf("Concat: DataFrame %d cannot be nil", i)
+		}
+		plan, err := df.asSubPlan()
+		if err != nil {
+			releaseHandleRefs(refs)
+			return NewDataFrame().appendErrOpf("Concat: DataFrame %d: %v", i, err)
+		}
+		inputs[i] = plan
+		refs = append(refs, plan.refs()...)
+		secret = secret || plan.secret()
+	}
+
 	// Create operation that will concatenate the DataFrames
 	op := Operation{
 		opcode: OpConcat,
 		args: func() unsafe.Pointer {
-			// Create array of handles
-			handles := make([]C.uintptr_t, len(dataframes))
-			for i, df := range dataframes {
-				if df.handle.handle == 0 {
-					// This will cause an error in Rust, which is what we want
-					handles[i] = 0
-				} else {
-					handles[i] = df.handle.handle
-				}
+			cInputs := make([]C.SubPlan, len(inputs))
+			for i, input := range inputs {
+				cInputs[i] = input.toC()
 			}
 
 			return unsafe.Pointer(&C.ConcatArgs{
-				handles: (*C.uintptr_t)(unsafe.Pointer(&handles[0])),
-				count:   C.size_t(len(handles)),
+				inputs: &cInputs[0],
+				count:  C.size_t(len(cInputs)),
 			})
 		},
+		refs:   refs,
+		secret: secret,
 	}
 
 	return &DataFrame{
 		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
-		operations: []Operation{op},
+		operations: []Operation{withCallSite(op)},
 	}
 }
 
@@ -369,6 +817,8 @@ func Concat(dataframes ...*DataFrame) *DataFrame {
 // Strings are automatically converted to SQL expressions, ExprNodes are used as-is
 // Example: df.WithColumns("salary * 1.1 as bonus", Col("age").Alias("years"))
 func (df *DataFrame) WithColumns(args ...any) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	exprs := toExprNodes(args...)
 
 	// Add all expression operations first
@@ -393,6 +843,8 @@ func (df *DataFrame) WithColumns(args ...any) *DataFrame {
 // Strings are automatically converted to SQL expressions, ExprNodes are used as-is
 // Example: df.Filter("age > 30") or df.Filter(Col("age").Gt(Lit(30)))
 func (df *DataFrame) Filter(arg any) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	exprs := toExprNodes(arg)
 	if len(exprs) != 1 {
 		return df.appendErrOp("Filter() requires exactly one expression")
@@ -439,6 +891,8 @@ func NoopCGOCall() {
 // Returns a DataFrame in LazyGroupBy context that can be used with Agg()
 // Example: df.GroupBy("department", "year(hire_date) as hire_year")
 func (df *DataFrame) GroupBy(args ...any) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	if len(args) == 0 {
 		return df.appendErrOp("GroupBy() requires at least one expression")
 	}
@@ -468,6 +922,8 @@ func (df *DataFrame) GroupBy(args ...any) *DataFrame {
 // Strings are automatically converted to SQL expressions, ExprNodes are used as-is
 // Example: df.GroupBy("department").Agg("avg(salary) as avg_salary", Col("age").Max().Alias("max_age"))
 func (df *DataFrame) Agg(args ...any) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	if len(args) == 0 {
 		return df.appendErrOp("Agg() requires at least one expression")
 	}
@@ -511,6 +967,8 @@ func (df *DataFrame) Sort(columns []string) *DataFrame {
 
 // SortBy sorts the DataFrame by the specified sort fields
 func (df *DataFrame) SortBy(fields []SortField) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	if len(fields) == 0 {
 		return df.appendErrOp("SortBy() requires at least one sort field")
 	}
@@ -545,6 +1003,8 @@ func (df *DataFrame) SortBy(fields []SortField) *DataFrame {
 
 // Limit limits the DataFrame to the first n rows
 func (df *DataFrame) Limit(n int) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	if n <= 0 {
 		return df.appendErrOp("Limit() requires n > 0")
 	}
@@ -562,6 +1022,54 @@ func (df *DataFrame) Limit(n int) *DataFrame {
 	return df
 }
 
+// Describe computes summary statistics for every column
+// The result has a "statistic" column (count, null_count, mean, std, min,
+// one row per percentile, max) followed by one column per input column.
+// Percentiles default to 0.25, 0.5 and 0.75 and must be within [0, 1].
+// Example: df.Describe(0.1, 0.9).Collect()
+func (df *DataFrame) Describe(percentiles ...float64) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
+	for _, p := range percentiles {
+		if p < 0 || p > 1 {
+			return df.appendErrOpf("Describe() percentile must be between 0 and 1, got %v", p)
+		}
+	}
+
+	op := Operation{
+		opcode: OpDescribe,
+		args: func() unsafe.Pointer {
+			if len(percentiles) == 0 {
+				return unsafe.Pointer(&C.DescribeArgs{})
+			}
+
+			values := make([]C.double, len(percentiles))
+			for i, p := range percentiles {
+				values[i] = C.double(p)
+			}
+
+			return unsafe.Pointer(&C.DescribeArgs{
+				percentiles:      &values[0],
+				percentile_count: C.size_t(len(values)),
+			})
+		},
+	}
+
+	df.operations = append(df.operations, op)
+	return df
+}
+
+// NullCount returns a DataFrame with a single row containing the number of nulls in each column
+func (df *DataFrame) NullCount() *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
+	df.operations = append(df.operations, Operation{
+		opcode: OpNullCount,
+		args:   noArgs,
+	})
+	return df
+}
+
 // addNullRowForTesting is an internal helper for testing null handling
 // It adds a single row with null values for all columns
 func (df *DataFrame) addNullRowForTesting() *DataFrame {
@@ -572,19 +1080,34 @@ func (df *DataFrame) addNullRowForTesting() *DataFrame {
 	return df
 }
 
+// raisePanicForTesting is an internal helper for testing panic recovery
+// It makes the Rust side panic when the operation is executed
+func (df *DataFrame) raisePanicForTesting() *DataFrame {
+	df.operations = append(df.operations, Operation{
+		opcode: OpRaisePanic,
+		args:   noArgs,
+	})
+	return df
+}
+
 // Release manually releases the DataFrame resources
+// Only this DataFrame's reference is dropped: a Join or Concat that already embeds it,
+// including one being collected on another goroutine, keeps the data alive until it runs
 func (df *DataFrame) Release() error {
+	df.mu.Lock()
+	defer df.mu.Unlock()
 	if df.handle.handle == 0 {
 		return nil // Already released or never executed
 	}
 
-	result := C.release_dataframe(df.handle.handle)
-	if result != 0 {
-		return errors.New("failed to release dataframe")
+	var err error
+	if df.ref != nil {
+		err = df.ref.release()
 	}
 
 	df.handle = C.PolarsHandle{} // Mark as released
-	return nil
+	df.ref = nil
+	return err
 }
 
 // ToCsv converts an executed DataFrame to a CSV string
@@ -593,9 +1116,12 @@ func (df *DataFrame) ToCsv() (string, error) {
 		return "", errors.New("dataframe not executed - call Execute() first")
 	}
 
-	csvPtr := C.dataframe_to_csv(df.handle.handle)
+	var errorCode C.int
+	var errorMessage *C.char
+	csvPtr := C.dataframe_to_csv(df.handle.handle, &errorCode, &errorMessage)
+	runtime.KeepAlive(df)
 	if csvPtr == nil {
-		return "", errors.New("failed to convert dataframe to CSV")
+		return "", ffiError(errorCode, errorMessage, "failed to convert dataframe to CSV")
 	}
 
 	csvString := C.GoString(csvPtr)
@@ -603,6 +1129,55 @@ func (df *DataFrame) ToCsv() (string, error) {
 	return csvString, nil
 }
 
+// ToJSON converts an executed DataFrame to a JSON array of row objects
+func (df *DataFrame) ToJSON() (string, error) {
+	if df.handle.handle == 0 {
+		return "", errors.New("dataframe not executed - call Execute() first")
+	}
+
+	var errorCode C.int
+	var errorMessage *C.char
+	jsonPtr := C.dataframe_to_json(df.handle.handle, &errorCode, &errorMessage)
+	runtime.KeepAlive(df)
+	if jsonPtr == nil {
+		return "", ffiError(errorCode, errorMessage, "failed to convert dataframe to JSON")
+	}
+
+	jsonString := C.GoString(jsonPtr)
+	C.free(unsafe.Pointer(jsonPtr)) // Free C memory
+	return jsonString, nil
+}
+
+// SchemaField describes a single column of an executed DataFrame
+type SchemaField struct {
+	Name  string `json:"name"`
+	DType string `json:"dtype"` // Polars dtype name, e.g. "i64", "str", "datetime[μs]"
+}
+
+// Schema returns the column names and data types of an executed DataFrame
+func (df *DataFrame) Schema() ([]SchemaField, error) {
+	if df.handle.handle == 0 {
+		return nil, errors.New("dataframe not executed - call Execute() first")
+	}
+
+	var errorCode C.int
+	var errorMessage *C.char
+	schemaPtr := C.dataframe_schema(df.handle.handle, &errorCode, &errorMessage)
+	runtime.KeepAlive(df)
+	if schemaPtr == nil {
+		return nil, ffiError(errorCode, errorMessage, "failed to get dataframe schema")
+	}
+
+	schemaJSON := C.GoString(schemaPtr)
+	C.free(unsafe.Pointer(schemaPtr)) // Free C memory
+
+	var fields []SchemaField
+	if err := json.Unmarshal([]byte(schemaJSON), &fields); err != nil {
+		return nil, fmt.Errorf("failed to decode dataframe schema: %w", err)
+	}
+	return fields, nil
+}
+
 // String implements fmt.Stringer for DataFrame display
 func (df *DataFrame) String() string {
 	if df.handle.handle == 0 {
@@ -612,9 +1187,13 @@ func (df *DataFrame) String() string {
 		return fmt.Sprintf("DataFrame{lazy: %d ops}", len(df.operations))
 	}
 
-	displayPtr := C.dataframe_to_string(df.handle.handle)
+	var errorCode C.int
+	var errorMessage *C.char
+	displayPtr := C.dataframe_to_string(df.handle.handle, &errorCode, &errorMessage)
+	runtime.KeepAlive(df)
 	if displayPtr == nil {
-		return fmt.Sprintf("DataFrame{handle: %d, error: failed to get display}", df.handle.handle)
+		err := ffiError(errorCode, errorMessage, "failed to get display")
+		return fmt.Sprintf("DataFrame{handle: %d, error: %v}", df.handle.handle, err)
 	}
 
 	displayString := C.GoString(displayPtr)
@@ -636,8 +1215,86 @@ func (df *DataFrame) Query(sql string) *DataFrame {
 		},
 	}
 
+	// The new DataFrame gets its own references, so collecting it cannot free df's handle
+	handle, ref := df.retain()
+	return &DataFrame{
+		handle:     handle,
+		ref:        ref,
+		operations: append(retainOperations(df.operations), withCallSite(op)),
+	}
+}
+
+// QueryParams executes a SQL query like Query, binding args to its placeholders
+// Placeholders are numbered ($1, $2, ...) or positional (?), and args supply their values
+// in order. Each value is bound as a typed SQL literal in the parsed query, which runs
+// without being turned back into SQL text, so untrusted input cannot change the query.
+// The query must be a single statement. Arguments may be signed integers, unsigned
+// integers up to uint32, floats, strings, bools or nil (SQL NULL).
+// Example: df.QueryParams("SELECT * FROM df WHERE name = $1 AND age > $2", name, 25)
+func (df *DataFrame) QueryParams(sql string, args ...any) *DataFrame {
+	op := Operation{
+		opcode: OpQuery,
+		args: func() unsafe.Pointer {
+			params := make([]C.Literal, len(args))
+			for i, arg := range args {
+				params[i], _ = queryParam(arg) // String arguments captured by closure
+			}
+
+			queryArgs := &C.QueryArgs{
+				sql:         makeRawStr(sql),
+				param_count: C.size_t(len(params)),
+			}
+			if len(params) > 0 {
+				queryArgs.params = &params[0]
+			}
+			return unsafe.Pointer(queryArgs)
+		},
+	}
+	for i, arg := range args {
+		if _, ok := queryParam(arg); !ok {
+			op = errOpf("QueryParams: argument %d has unsupported type %T", i+1, arg)
+			break
+		}
+	}
+
+	handle, ref := df.retain()
 	return &DataFrame{
-		handle:     df.handle,
-		operations: append(df.operations, op),
+		handle:     handle,
+		ref:        ref,
+		operations: append(retainOperations(df.operations), withCallSite(op)),
+	}
+}
+
+// queryParam converts a QueryParams argument to a Literal
+func queryParam(value any) (C.Literal, bool) {
+	switch v := value.(type) {
+	case nil:
+		return C.Literal{value_type: 4}, true
+	case int:
+		return C.Literal{value_type: 0, int_value: C.longlong(v)}, true
+	case int8:
+		return C.Literal{value_type: 0, int_value: C.longlong(v)}, true
+	case int16:
+		return C.Literal{value_type: 0, int_value: C.longlong(v)}, true
+	case int32:
+		return C.Literal{value_type: 0, int_value: C.longlong(v)}, true
+	case int64:
+		return C.Literal{value_type: 0, int_value: C.longlong(v)}, true
+	case uint8:
+		return C.Literal{value_type: 0, int_value: C.longlong(v)}, true
+	case uint16:
+		return C.Literal{value_type: 0, int_value: C.longlong(v)}, true
+	case uint32:
+		return C.Literal{value_type: 0, int_value: C.longlong(v)}, true
+	case float32:
+		return C.Literal{value_type: 1, float_value: C.double(v)}, true
+	case float64:
+		return C.Literal{value_type: 1, float_value: C.double(v)}, true
+	case string:
+		return C.Literal{value_type: 2, string_value: makeRawStr(v)}, true
+	case bool:
+		return C.Literal{value_type: 3, bool_value: C._Bool(v)}, true
+	default:
+		return C.Literal{}, false
 	}
 }
@@ -1,8 +1,25 @@
 package polars
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"testing/iotest"
 	"time"
 
 	"github.com/stretchr/testify/require"
@@ -35,6 +52,80 @@ func TestBasicOperations(t *testing.T) {
 		require.Equal(t, expected, result.String())
 	})
 
+	t.Run("ReadCSVWithOptions", func(t *testing.T) {
+		df := ReadCSVWithOptions("../testdata/options.csv", CSVOptions{
+			HasHeader:       true,
+			Separator:       ';',
+			CommentPrefix:   "#",
+			NullValues:      []string{"NA"},
+			SchemaOverrides: map[string]DataType{"zip": String},
+			TryParseDates:   true,
+			RowIndexName:    "row_nr",
+			RowIndexOffset:  1,
+		})
+		result, err := df.Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		// Golden test: zip keeps leading zeros, NA becomes null, dates are parsed
+		expected := `shape: (3, 5)
+┌────────┬─────┬───────┬───────┬────────────┐
+│ row_nr ┆ id  ┆ zip   ┆ score ┆ joined     │
+│ ---    ┆ --- ┆ ---   ┆ ---   ┆ ---        │
+│ u32    ┆ i64 ┆ str   ┆ f64   ┆ date       │
+╞════════╪═════╪═══════╪═══════╪════════════╡
+│ 1      ┆ 1   ┆ 02134 ┆ 9.5   ┆ 2024-01-15 │
+│ 2      ┆ 2   ┆ null  ┆ 7.25  ┆ 2024-02-01 │
+│ 3      ┆ 3   ┆ 10001 ┆ null  ┆ 2024-03-10 │
+└────────┴─────┴───────┴───────┴────────────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("ReadCSVRowLimit", func(t *testing.T) {
+		options := DefaultCSVOptions()
+		options.NRows = 2
+		result, err := ReadCSVWithOptions("../testdata/small.csv", options).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (2, 2)
+┌─────┬───────┐
+│ id  ┆ value │
+│ --- ┆ ---   │
+│ i64 ┆ i64   │
+╞═════╪═══════╡
+│ 1   ┆ 100   │
+│ 2   ┆ 200   │
+└─────┴───────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("ReadCSVZeroOptions", func(t *testing.T) {
+		// HasHeader is false in a bare CSVOptions, so the header line is data
+		result, err := ReadCSVWithOptions("../testdata/small.csv", CSVOptions{}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 4, height)
+		csv, err := result.ToCsv()
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(csv, "column_1,column_2\nid,value\n"), csv)
+	})
+
+	t.Run("ReadCSVInvalidOptions", func(t *testing.T) {
+		_, err := ReadCSVWithOptions("../testdata/small.csv", CSVOptions{NRows: -1}).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot be negative")
+
+		_, err = ReadCSVWithOptions("../testdata/small.csv", CSVOptions{Separator: '"', QuoteChar: '"'}).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "separator and quote char")
+	})
+
 	t.Run("Select", func(t *testing.T) {
 		df := ReadCSV("../testdata/sample.csv")
 		result, err := df.Select("name", "salary").Collect()
@@ -99,6 +190,72 @@ func TestBasicOperations(t *testing.T) {
 
 		require.Equal(t, expected, result.String())
 	})
+
+	t.Run("SchemaAndJSON", func(t *testing.T) {
+		df := ReadCSV("../testdata/small.csv")
+		result, err := df.Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		schema, err := result.Schema()
+		require.NoError(t, err)
+		require.Equal(t, []SchemaField{{Name: "id", DType: "i64"}, {Name: "value", DType: "i64"}}, schema)
+
+		json, err := result.ToJSON()
+		require.NoError(t, err)
+		require.Equal(t, `[{"id":1,"value":100},{"id":2,"value":200},{"id":3,"value":300}]`, json)
+	})
+
+	t.Run("Describe", func(t *testing.T) {
+		df := ReadCSV("../testdata/small.csv")
+		result, err := df.Describe(0.5).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		// Golden test: one row per statistic, one column per input column
+		expected := `shape: (7, 3)
+┌────────────┬─────┬───────┐
+│ statistic  ┆ id  ┆ value │
+│ ---        ┆ --- ┆ ---   │
+│ str        ┆ f64 ┆ f64   │
+╞════════════╪═════╪═══════╡
+│ count      ┆ 3.0 ┆ 3.0   │
+│ null_count ┆ 0.0 ┆ 0.0   │
+│ mean       ┆ 2.0 ┆ 200.0 │
+│ std        ┆ 1.0 ┆ 100.0 │
+│ min        ┆ 1.0 ┆ 100.0 │
+│ 50%        ┆ 2.0 ┆ 200.0 │
+│ max        ┆ 3.0 ┆ 300.0 │
+└────────────┴─────┴───────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("DescribeInvalidPercentile", func(t *testing.T) {
+		df := ReadCSV("../testdata/small.csv")
+		_, err := df.Describe(1.5).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "percentile must be between 0 and 1")
+	})
+
+	t.Run("NullCount", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.NullCount().Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		// Golden test: sample data has no nulls
+		expected := `shape: (1, 4)
+┌──────┬─────┬────────┬────────────┐
+│ name ┆ age ┆ salary ┆ department │
+│ ---  ┆ --- ┆ ---    ┆ ---        │
+│ u32  ┆ u32 ┆ u32    ┆ u32        │
+╞══════╪═════╪════════╪════════════╡
+│ 0    ┆ 0   ┆ 0      ┆ 0          │
+└──────┴─────┴────────┴────────────┘`
+
+		require.Equal(t, expected, result.String())
+	})
 }
 
 // TestExpressions demonstrates expression operations with clear examples
@@ -489,6 +646,122 @@ func TestAggregations(t *testing.T) {
 
 		require.Equal(t, expected, result.String())
 	})
+
+	t.Run("ExtendedAggregationsInSelect", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.SelectExpr(
+			Col("salary").Quantile(0.5, QuantileNearest).Alias("p50"),
+			Col("salary").ArgMax().Alias("argmax"),
+			Col("salary").ArgMin().Alias("argmin"),
+			Col("department").Mode().Alias("mode"),
+			Col("age").Product().Alias("age_product"),
+		).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		// Golden test: extended aggregations reduce to a single row
+		expected := `shape: (1, 5)
+┌─────────┬────────┬────────┬─────────────┬─────────────┐
+│ p50     ┆ argmax ┆ argmin ┆ mode        ┆ age_product │
+│ ---     ┆ ---    ┆ ---    ┆ ---         ┆ ---         │
+│ f64     ┆ u32    ┆ u32    ┆ str         ┆ i64         │
+╞═════════╪════════╪════════╪═════════════╪═════════════╡
+│ 58000.0 ┆ 2      ┆ 0      ┆ Engineering ┆ 18416160000 │
+└─────────┴────────┴────────┴─────────────┴─────────────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("ExtendedAggregationsInAgg", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.GroupBy("department").
+			Agg(
+				Col("salary").Quantile(0.5, QuantileLinear).Alias("median_salary"),
+				Col("age").Product().Alias("age_product"),
+			).
+			Sort([]string{"department"}).
+			Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		// Golden test: linear interpolation between the two middle values of even-sized groups
+		expected := `shape: (3, 3)
+┌─────────────┬───────────────┬─────────────┐
+│ department  ┆ median_salary ┆ age_product │
+│ ---         ┆ ---           ┆ ---         │
+│ str         ┆ f64           ┆ i64         │
+╞═════════════╪═══════════════╪═════════════╡
+│ Engineering ┆ 65000.0       ┆ 28000       │
+│ Marketing   ┆ 59000.0       ┆ 870         │
+│ Sales       ┆ 53500.0       ┆ 756         │
+└─────────────┴───────────────┴─────────────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("CollectingAggregationsInSelect", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.SelectExpr(
+			Col("department").ApproxNUnique().Alias("departments"),
+			Col("age").Implode().Alias("ages"),
+		).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		rows, err := result.ToJSON()
+		require.NoError(t, err)
+		require.Equal(t, `[{"departments":3,"ages":[25,30,35,28,32,29,27]}]`, rows)
+	})
+
+	t.Run("CollectingAggregationsInAgg", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.GroupBy("department").
+			Agg(
+				Col("age").ApproxNUnique().Alias("distinct_ages"),
+				Col("age").Implode().Alias("ages"),
+				Col("name").AggGroups().Alias("rows"),
+			).
+			Sort([]string{"department"}).
+			Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		rows, err := result.ToJSON()
+		require.NoError(t, err)
+		var groups []map[string]any
+		require.NoError(t, json.Unmarshal([]byte(rows), &groups))
+		require.Len(t, groups, 3)
+
+		// Row indices refer to the input rows of each group, in input order
+		for i, group := range []struct {
+			department   string
+			distinctAges float64
+			ages         string
+			rows         []any
+		}{
+			{"Engineering", 3, "[25,35,32]", []any{float64(0), float64(2), float64(4)}},
+			{"Marketing", 2, "[30,29]", []any{float64(1), float64(5)}},
+			{"Sales", 2, "[28,27]", []any{float64(3), float64(6)}},
+		} {
+			require.Equal(t, group.department, groups[i]["department"])
+			require.Equal(t, group.distinctAges, groups[i]["distinct_ages"])
+			require.Equal(t, group.rows, groups[i]["rows"])
+			ages, err := json.Marshal(groups[i]["ages"])
+			require.NoError(t, err)
+			require.Contains(t, string(ages), group.ages) // Each group's ages as one list
+		}
+	})
+
+	t.Run("ExtendedAggregationValidation", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		_, err := df.SelectExpr(Col("salary").Quantile(2, QuantileLinear)).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Quantile() quantile must be between 0 and 1")
+
+		// Column + Skew/Kurtosis + Alias
+		require.Equal(t, 3, Col("salary").Skew(false).Alias("skew").countOps())
+		require.Equal(t, 3, Col("salary").Kurtosis(true, true).Alias("kurt").countOps())
+	})
 }
 
 // TestSQLExpressions demonstrates the key ...any functionality with SQL strings
@@ -868,7 +1141,7 @@ func TestPerformanceBenchmarks(t *testing.T) {
 		}
 
 		// Load all 10 files from scripts/testdata (100M rows total) using glob pattern
-		df := ReadCSVWithOptions("../scripts/testdata/weather_data_part_*.csv", true, true)
+		df := ReadCSVWithOptions("../scripts/testdata/weather_data_part_*.csv", DefaultCSVOptions())
 
 		// Test complex aggregation on 100M rows
 		start := time.Now()
@@ -910,7 +1183,7 @@ func TestPerformanceBenchmarks(t *testing.T) {
 		}
 
 		// Test with filter that matches nothing (impossible temperatures)
-		df := ReadCSVWithOptions("../scripts/testdata/weather_data_part_*.csv", true, true)
+		df := ReadCSVWithOptions("../scripts/testdata/weather_data_part_*.csv", DefaultCSVOptions())
 
 		start := time.Now()
 		result, err := df.Filter(
@@ -1035,6 +1308,60 @@ func TestWindowFunctions(t *testing.T) {
 	})
 }
 
+// TestRollingFunctions demonstrates rolling window, cumulative and difference expressions
+func TestRollingFunctions(t *testing.T) {
+	t.Run("FixedWindowAndCumulative", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.Select(
+			Col("name"),
+			Col("salary"),
+			Col("salary").RollingSum(3, 0, false).Alias("rolling_sum"),
+			Col("salary").RollingMax(3, 0, false).Alias("rolling_max"),
+			Col("salary").CumSum().Alias("cum_sum"),
+			Col("salary").CumMax().Alias("cum_max"),
+			Col("salary").Diff(1).Alias("diff"),
+		).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		// Golden test: rolling windows need 3 rows before producing a value
+		expected := `shape: (7, 7)
+┌─────────┬────────┬─────────────┬─────────────┬─────────┬─────────┬────────┐
+│ name    ┆ salary ┆ rolling_sum ┆ rolling_max ┆ cum_sum ┆ cum_max ┆ diff   │
+│ ---     ┆ ---    ┆ ---         ┆ ---         ┆ ---     ┆ ---     ┆ ---    │
+│ str     ┆ i64    ┆ i64         ┆ i64         ┆ i64     ┆ i64     ┆ i64    │
+╞═════════╪════════╪═════════════╪═════════════╪═════════╪═════════╪════════╡
+│ Alice   ┆ 50000  ┆ null        ┆ null        ┆ 50000   ┆ 50000   ┆ null   │
+│ Bob     ┆ 60000  ┆ null        ┆ null        ┆ 110000  ┆ 60000   ┆ 10000  │
+│ Charlie ┆ 70000  ┆ 180000      ┆ 70000       ┆ 180000  ┆ 70000   ┆ 10000  │
+│ Diana   ┆ 55000  ┆ 185000      ┆ 70000       ┆ 235000  ┆ 70000   ┆ -15000 │
+│ Eve     ┆ 65000  ┆ 190000      ┆ 70000       ┆ 300000  ┆ 70000   ┆ 10000  │
+│ Frank   ┆ 58000  ┆ 178000      ┆ 65000       ┆ 358000  ┆ 70000   ┆ -7000  │
+│ Grace   ┆ 52000  ┆ 175000      ┆ 65000       ┆ 410000  ┆ 70000   ┆ -6000  │
+└─────────┴────────┴─────────────┴─────────────┴─────────┴─────────┴────────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("RollingOpCounts", func(t *testing.T) {
+		// Column + Rolling + Alias
+		require.Equal(t, 3, Col("salary").RollingMean(3, 1, true).Alias("m").countOps())
+		require.Equal(t, 3, Col("salary").RollingMeanBy("date", "7d").Alias("m").countOps())
+		require.Equal(t, 2, Col("salary").PctChange(1).countOps())
+	})
+
+	t.Run("InvalidWindow", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		_, err := df.Select(Col("salary").RollingMean(0, 0, false)).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "RollingMean() window size must be positive")
+
+		_, err = ReadCSV("../testdata/sample.csv").Select(Col("salary").RollingQuantile(1.5, 3, 0, false)).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "quantile must be between 0 and 1")
+	})
+}
+
 // TestJoinOperations demonstrates join functionality with all join types
 func TestJoinOperations(t *testing.T) {
 	t.Run("BasicInnerJoin", func(t *testing.T) {
@@ -1224,6 +1551,66 @@ func TestJoinOperations(t *testing.T) {
 
 		require.Equal(t, expected, result.String())
 	})
+
+	t.Run("LazyJoin", func(t *testing.T) {
+		// Neither side is collected: the right side is embedded as a sub-plan
+		left := ReadCSV("../testdata/sample.csv").Select("name", "salary")
+		right := ReadCSV("../testdata/sample.csv").
+			Select("name", "department").
+			Filter(Col("department").Eq(Lit("Engineering")))
+
+		result, err := left.InnerJoin(right, "name").Sort([]string{"name"}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (3, 3)
+┌─────────┬────────┬─────────────┐
+│ name    ┆ salary ┆ department  │
+│ ---     ┆ ---    ┆ ---         │
+│ str     ┆ i64    ┆ str         │
+╞═════════╪════════╪═════════════╡
+│ Alice   ┆ 50000  ┆ Engineering │
+│ Charlie ┆ 70000  ┆ Engineering │
+│ Eve     ┆ 65000  ┆ Engineering │
+└─────────┴────────┴─────────────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("LazyConcat", func(t *testing.T) {
+		result, err := Concat(
+			ReadCSV("../testdata/small.csv"),
+			ReadCSV("../testdata/small.csv").Filter(Col("id").Gt(Lit(2))),
+		).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (4, 2)
+┌─────┬───────┐
+│ id  ┆ value │
+│ --- ┆ ---   │
+│ i64 ┆ i64   │
+╞═════╪═══════╡
+│ 1   ┆ 100   │
+│ 2   ┆ 200   │
+│ 3   ┆ 300   │
+│ 3   ┆ 300   │
+└─────┴───────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("SubPlanErrors", func(t *testing.T) {
+		left := ReadCSV("../testdata/sample.csv")
+		right := ReadCSV("../testdata/sample.csv").Limit(0)
+		_, err := left.InnerJoin(right, "name").Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Join: other DataFrame: Limit() requires n > 0")
+
+		_, err = Concat(ReadCSV("../testdata/small.csv"), NewDataFrame().Limit(0)).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "Concat: DataFrame 1")
+	})
 }
 
 // TestParquetOperations demonstrates Parquet file reading capabilities focused on Firn integration
@@ -1262,7 +1649,6 @@ func TestParquetOperations(t *testing.T) {
 			Columns:  []string{"Rank", "Company", "Ticker"}, // Column selection
 			NRows:    3,                                     // Row limiting
 			Parallel: true,                                  // Parallel reading
-			WithGlob: false,                                 // No glob expansion
 		})
 		result, err := df.
 			Filter(Col("Rank").Lt(Lit(4))). // Further filter to top 3
@@ -1314,6 +1700,47 @@ func TestParquetOperations(t *testing.T) {
 		require.Equal(t, expected, result.String())
 	})
 
+	t.Run("ParquetProjectionWithScanOptions", func(t *testing.T) {
+		// Column projection must not discard the other scan settings
+		result, err := ReadParquetWithOptions("../testdata/fortune1000_2024.parquet", ParquetOptions{
+			Columns:           []string{"Rank", "Company"},
+			NRows:             2,
+			Parallel:          false,
+			LowMemory:         true,
+			DisableStatistics: true,
+			Rechunk:           true,
+		}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		out, err := result.ToCsv()
+		require.NoError(t, err)
+		require.Equal(t, "Rank,Company\n1,Walmart\n2,Amazon\n", out)
+	})
+
+	t.Run("ParquetPredicatePushdown", func(t *testing.T) {
+		// The predicate may reference a column that is not selected
+		result, err := ReadParquetWithOptions("../testdata/fortune1000_2024.parquet", ParquetOptions{
+			Columns:   []string{"Company"},
+			Parallel:  true,
+			Predicate: Col("Rank").Lt(Lit(4)),
+		}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		out, err := result.ToCsv()
+		require.NoError(t, err)
+		require.Equal(t, "Company\nWalmart\nAmazon\nApple\n", out)
+	})
+
+	t.Run("ParquetInvalidPredicate", func(t *testing.T) {
+		_, err := ReadParquetWithOptions("../testdata/fortune1000_2024.parquet", ParquetOptions{
+			Predicate: toExprNodes(42)[0],
+		}).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "predicate")
+	})
+
 	t.Run("ParquetErrorHandling", func(t *testing.T) {
 		// Test error handling for invalid Parquet files
 		df := ReadParquet("../testdata/nonexistent.parquet")
@@ -1321,6 +1748,1550 @@ func TestParquetOperations(t *testing.T) {
 		require.Error(t, err)
 		require.Contains(t, err.Error(), "polars error")
 	})
+
+	t.Run("ParquetMetadata", func(t *testing.T) {
+		// Footer metadata must agree with a full scan
+		metadata, err := ReadParquetMetadata("../testdata/fortune1000_2024.parquet")
+		require.NoError(t, err)
+
+		result, err := ReadParquet("../testdata/fortune1000_2024.parquet").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, int64(height), metadata.NumRows)
+
+		require.Positive(t, metadata.NumRowGroups())
+		var rowGroupRows int64
+		for _, rg := range metadata.RowGroups {
+			rowGroupRows += rg.NumRows
+			require.NotEmpty(t, rg.Columns)
+		}
+		require.Equal(t, metadata.NumRows, rowGroupRows)
+
+		var paths []string
+		for _, column := range metadata.RowGroups[0].Columns {
+			paths = append(paths, column.Path)
+			require.NotEmpty(t, column.Compression)
+			require.Positive(t, column.CompressedSize)
+		}
+		require.Contains(t, paths, "Rank")
+		require.Contains(t, paths, "Company")
+	})
+
+	t.Run("ParquetMetadataMissingFile", func(t *testing.T) {
+		_, err := ReadParquetMetadata("../testdata/nonexistent.parquet")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "nonexistent.parquet")
+	})
+}
+
+// TestJSONOperations demonstrates NDJSON and JSON reading
+func TestJSONOperations(t *testing.T) {
+	t.Run("ReadNDJSON", func(t *testing.T) {
+		result, err := ReadNDJSON("../testdata/events.ndjson", NDJSONOptions{NRows: 2}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (2, 3)
+┌───────┬───────┬─────┐
+│ user  ┆ event ┆ ms  │
+│ ---   ┆ ---   ┆ --- │
+│ str   ┆ str   ┆ i64 │
+╞═══════╪═══════╪═════╡
+│ alice ┆ login ┆ 120 │
+│ bob   ┆ click ┆ 45  │
+└───────┴───────┴─────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("ReadNDJSONGlob", func(t *testing.T) {
+		result, err := ReadNDJSON("../testdata/event*.ndjson", NDJSONOptions{}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 3, height)
+	})
+
+	t.Run("ReadJSON", func(t *testing.T) {
+		result, err := ReadJSON("../testdata/events.json").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (3, 3)
+┌───────┬────────┬─────┐
+│ user  ┆ event  ┆ ms  │
+│ ---   ┆ ---    ┆ --- │
+│ str   ┆ str    ┆ i64 │
+╞═══════╪════════╪═════╡
+│ alice ┆ login  ┆ 120 │
+│ bob   ┆ click  ┆ 45  │
+│ alice ┆ logout ┆ 80  │
+└───────┴────────┴─────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("JSONErrorHandling", func(t *testing.T) {
+		_, err := ReadJSON("../testdata/nonexistent.json").Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "nonexistent.json")
+
+		_, err = ReadNDJSON("../testdata/events.ndjson", NDJSONOptions{NRows: -1}).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot be negative")
+	})
+}
+
+// TestIPCOperations demonstrates Arrow IPC file and stream round trips
+func TestIPCOperations(t *testing.T) {
+	expected := `shape: (3, 2)
+┌─────┬───────┐
+│ id  ┆ value │
+│ --- ┆ ---   │
+│ i64 ┆ i64   │
+╞═════╪═══════╡
+│ 1   ┆ 100   │
+│ 2   ┆ 200   │
+│ 3   ┆ 300   │
+└─────┴───────┘`
+
+	source, err := ReadCSV("../testdata/small.csv").Collect()
+	require.NoError(t, err)
+	defer source.Release()
+
+	t.Run("FileRoundTrip", func(t *testing.T) {
+		for _, compression := range []IPCCompression{IPCCompressionNone, IPCCompressionLZ4, IPCCompressionZSTD} {
+			path := filepath.Join(t.TempDir(), "small.arrow")
+			require.NoError(t, source.WriteIPC(path, compression))
+
+			eager, err := ReadIPC(path).Collect()
+			require.NoError(t, err)
+			require.Equal(t, expected, eager.String())
+			eager.Release()
+
+			lazy, err := ScanIPC(path).Filter(Col("id").Gt(Lit(1))).Collect()
+			require.NoError(t, err)
+			height, err := lazy.Height()
+			require.NoError(t, err)
+			require.Equal(t, 2, height)
+			lazy.Release()
+		}
+	})
+
+	t.Run("StreamRoundTrip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, source.WriteIPCStream(&buf, IPCCompressionLZ4))
+
+		result, err := ReadIPCStream(&buf).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("IPCErrorHandling", func(t *testing.T) {
+		_, err := ReadIPC("../testdata/nonexistent.arrow").Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "nonexistent.arrow")
+
+		_, err = ReadIPCStream(bytes.NewReader(nil)).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "stream is empty")
+
+		require.Error(t, NewDataFrame().WriteIPC(filepath.Join(t.TempDir(), "x.arrow"), IPCCompressionNone))
+	})
+}
+
+// TestReaderWriterIO covers reading from io.Reader and writing to io.Writer without temp files
+func TestReaderWriterIO(t *testing.T) {
+	expected := `shape: (3, 2)
+┌─────┬───────┐
+│ id  ┆ value │
+│ --- ┆ ---   │
+│ i64 ┆ i64   │
+╞═════╪═══════╡
+│ 1   ┆ 100   │
+│ 2   ┆ 200   │
+│ 3   ┆ 300   │
+└─────┴───────┘`
+
+	source, err := ReadCSV("../testdata/small.csv").Collect()
+	require.NoError(t, err)
+	defer source.Release()
+
+	t.Run("CSVRoundTrip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, source.WriteCSVTo(&buf))
+		require.Equal(t, "id,value\n1,100\n2,200\n3,300\n", buf.String())
+
+		result, err := ReadCSVFrom(&buf, DefaultCSVOptions()).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("CSVFromWithOptions", func(t *testing.T) {
+		input := bytes.NewReader([]byte("id;value\n1;NA\n2;200\n"))
+		result, err := ReadCSVFrom(input, CSVOptions{
+			HasHeader:  true,
+			Separator:  ';',
+			NullValues: []string{"NA"},
+		}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		out, err := result.ToCsv()
+		require.NoError(t, err)
+		require.Equal(t, "id,value\n1,\n2,200\n", out)
+	})
+
+	t.Run("ParquetRoundTrip", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, source.WriteParquetTo(&buf))
+
+		data := buf.Bytes()
+		result, err := ReadParquetFrom(bytes.NewReader(data), int64(len(data))).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("ParquetFromFile", func(t *testing.T) {
+		file, err := os.Open("../testdata/fortune1000_2024.parquet")
+		require.NoError(t, err)
+		defer file.Close()
+		info, err := file.Stat()
+		require.NoError(t, err)
+
+		fromReader, err := ReadParquetFrom(file, info.Size()).Count().Collect()
+		require.NoError(t, err)
+		defer fromReader.Release()
+
+		fromPath, err := ReadParquet("../testdata/fortune1000_2024.parquet").Count().Collect()
+		require.NoError(t, err)
+		defer fromPath.Release()
+
+		require.Equal(t, fromPath.String(), fromReader.String())
+	})
+
+	t.Run("NDJSONFrom", func(t *testing.T) {
+		file, err := os.Open("../testdata/events.ndjson")
+		require.NoError(t, err)
+		defer file.Close()
+
+		result, err := ReadNDJSONFrom(file).Filter(Col("user").Eq(Lit("alice"))).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 2, height)
+	})
+
+	t.Run("ReaderWriterErrors", func(t *testing.T) {
+		_, err := ReadCSVFrom(bytes.NewReader(nil), DefaultCSVOptions()).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "input is empty")
+
+		_, err = ReadParquetFrom(bytes.NewReader([]byte("not parquet")), 11).Collect()
+		require.Error(t, err)
+
+		_, err = ReadParquetFrom(bytes.NewReader([]byte("short")), 100).Collect()
+		require.Error(t, err)
+
+		require.Error(t, NewDataFrame().WriteCSVTo(&bytes.Buffer{}))
+	})
+}
+
+// TestHivePartitioning writes a partitioned dataset and scans it back with partition pruning
+func TestHivePartitioning(t *testing.T) {
+	source, err := ReadCSV("../testdata/sample.csv").Collect()
+	require.NoError(t, err)
+	defer source.Release()
+
+	dir := t.TempDir()
+	require.NoError(t, source.WriteParquetPartitioned(dir, "department"))
+
+	for _, department := range []string{"Engineering", "Marketing", "Sales"} {
+		_, err := os.Stat(filepath.Join(dir, "department="+department, "00000000.parquet"))
+		require.NoError(t, err)
+	}
+
+	t.Run("ReadWithPruning", func(t *testing.T) {
+		result, err := ReadParquetWithOptions(dir, ParquetOptions{
+			Parallel:         true,
+			HivePartitioning: true,
+			HiveSchema:       map[string]DataType{"department": String},
+		}).Filter(Col("department").Eq(Lit("Engineering"))).
+			Sort([]string{"name"}).
+			Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		// Golden test: partition column is restored after the data columns
+		expected := `shape: (3, 4)
+┌─────────┬─────┬────────┬─────────────┐
+│ name    ┆ age ┆ salary ┆ department  │
+│ ---     ┆ --- ┆ ---    ┆ ---         │
+│ str     ┆ i64 ┆ i64    ┆ str         │
+╞═════════╪═════╪════════╪═════════════╡
+│ Alice   ┆ 25  ┆ 50000  ┆ Engineering │
+│ Charlie ┆ 35  ┆ 70000  ┆ Engineering │
+│ Eve     ┆ 32  ┆ 65000  ┆ Engineering │
+└─────────┴─────┴────────┴─────────────┘`
+
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("SecondWriteAppends", func(t *testing.T) {
+		dir := t.TempDir()
+		require.NoError(t, source.WriteParquetPartitioned(dir, "department"))
+		require.NoError(t, source.WriteParquetPartitioned(dir, "department"))
+
+		files, err := filepath.Glob(filepath.Join(dir, "department=Sales", "*.parquet"))
+		require.NoError(t, err)
+		require.Equal(t, []string{
+			filepath.Join(dir, "department=Sales", "00000000.parquet"),
+			filepath.Join(dir, "department=Sales", "00000001.parquet"),
+		}, files)
+
+		result, err := ReadParquetWithOptions(dir, ParquetOptions{Parallel: true, HivePartitioning: true}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 14, height) // Both writes of the 7 rows
+	})
+
+	t.Run("PartitionedWriteErrors", func(t *testing.T) {
+		require.Error(t, source.WriteParquetPartitioned(t.TempDir()))
+		require.Error(t, source.WriteParquetPartitioned(t.TempDir(), "missing"))
+		require.Error(t, NewDataFrame().WriteParquetPartitioned(t.TempDir(), "department"))
+	})
+}
+
+// newS3StandIn serves objects ("bucket/key" to contents) over the parts of the S3 API a
+// scan uses: HEAD and ranged GET with path-style URLs. Signatures are not checked, but
+// every request must be signed with accessKeyID.
+func newS3StandIn(t *testing.T, accessKeyID string, objects map[string][]byte) string {
+	modified := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Authorization"), "Credential="+accessKeyID+"/") {
+			http.Error(w, "missing or foreign credentials", http.StatusForbidden)
+			return
+		}
+		data, ok := objects[strings.TrimPrefix(r.URL.Path, "/")]
+		if !ok || (r.Method != http.MethodGet && r.Method != http.MethodHead) {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("ETag", `"firn"`)
+		http.ServeContent(w, r, r.URL.Path, modified, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+// TestCloudOptions reads from S3-compatible storage through an in-process stand-in
+func TestCloudOptions(t *testing.T) {
+	parquet, err := os.ReadFile("../testdata/fortune1000_2024.parquet")
+	require.NoError(t, err)
+	csv, err := os.ReadFile("../testdata/small.csv")
+	require.NoError(t, err)
+	endpoint := newS3StandIn(t, "firn-key", map[string][]byte{
+		"firn/fortune1000_2024.parquet": parquet,
+		"firn/small.csv":                csv,
+	})
+	newCloud := func() *CloudOptions {
+		return &CloudOptions{
+			Endpoint:    endpoint,
+			Region:      "us-east-1",
+			Credentials: CloudCredentials{AccessKeyID: "firn-key", SecretAccessKey: "firn-secret"},
+			AllowHTTP:   true,
+		}
+	}
+
+	t.Run("InvalidOptions", func(t *testing.T) {
+		_, err := ReadParquetWithOptions("s3://bucket/data.parquet", ParquetOptions{
+			Cloud: &CloudOptions{Endpoint: "http://localhost:9000"},
+		}).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "AllowHTTP")
+
+		_, err = ReadCSVWithOptions("s3://bucket/data.csv", CSVOptions{
+			HasHeader: true,
+			Cloud:     &CloudOptions{Credentials: CloudCredentials{AccessKeyID: "key"}},
+		}).Collect()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "SecretAccessKey")
+	})
+
+	t.Run("ReadParquet", func(t *testing.T) {
+		remote, err := ReadParquetWithOptions("s3://firn/fortune1000_2024.parquet", ParquetOptions{
+			Parallel: true,
+			Columns:  []string{"Rank", "Company"},
+			Cloud:    newCloud(),
+		}).Filter(Col("Rank").Lt(Lit(4))).Collect()
+		require.NoError(t, err)
+		defer remote.Release()
+
+		local, err := ReadParquetWithOptions("../testdata/fortune1000_2024.parquet", ParquetOptions{
+			Columns: []string{"Rank", "Company"},
+		}).Filter(Col("Rank").Lt(Lit(4))).Collect()
+		require.NoError(t, err)
+		defer local.Release()
+
+		require.Equal(t, local.String(), remote.String())
+	})
+
+	t.Run("ReadCSV", func(t *testing.T) {
+		options := DefaultCSVOptions()
+		options.Cloud = newCloud()
+		result, err := ReadCSVWithOptions("s3://firn/small.csv", options).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		out, err := result.ToCsv()
+		require.NoError(t, err)
+		require.Equal(t, string(csv), out)
+	})
+
+	t.Run("WrongCredentials", func(t *testing.T) {
+		cloud := newCloud()
+		cloud.Credentials.AccessKeyID = "other-key"
+		_, err := ReadParquetWithOptions("s3://firn/fortune1000_2024.parquet", ParquetOptions{Cloud: cloud}).Collect()
+		require.Error(t, err)
+	})
+
+	t.Run("OptionsCopiedWhenBuilt", func(t *testing.T) {
+		cloud := newCloud()
+		scan := ReadParquetWithOptions("s3://firn/fortune1000_2024.parquet", ParquetOptions{Cloud: cloud})
+
+		// Changes after building affect neither the read nor the credential check
+		*cloud = CloudOptions{Endpoint: "http://localhost:1"}
+		_, err := scan.MarshalPlan()
+		require.ErrorContains(t, err, "static cloud credentials")
+
+		result, err := scan.Count().Collect()
+		require.NoError(t, err)
+		defer result.Release()
+	})
+}
+
+// TestScanGeneratedColumns covers row index and source file columns across globbed files
+func TestScanGeneratedColumns(t *testing.T) {
+	source, err := ReadCSV("../testdata/small.csv").Collect()
+	require.NoError(t, err)
+	defer source.Release()
+
+	dir := t.TempDir()
+	for _, name := range []string{"a.parquet", "b.parquet"} {
+		var buf bytes.Buffer
+		require.NoError(t, source.WriteParquetTo(&buf))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), buf.Bytes(), 0o644))
+	}
+
+	t.Run("ParquetRowIndexAcrossFiles", func(t *testing.T) {
+		result, err := ReadParquetWithOptions(filepath.Join(dir, "*.parquet"), ParquetOptions{
+			Columns:        []string{"id"},
+			RowIndexName:   "row",
+			RowIndexOffset: 1,
+		}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		out, err := result.ToCsv()
+		require.NoError(t, err)
+		require.Equal(t, "row,id\n1,1\n2,2\n3,3\n4,1\n5,2\n6,3\n", out)
+	})
+
+	t.Run("ParquetGlobWithZeroOptions", func(t *testing.T) {
+		// WithGlob is ignored: false, the zero value, still expands the pattern
+		for _, options := range []ParquetOptions{{}, {WithGlob: false}, {WithGlob: true}} {
+			result, err := ReadParquetWithOptions(filepath.Join(dir, "*.parquet"), options).Collect()
+			require.NoError(t, err)
+			height, err := result.Height()
+			require.NoError(t, err)
+			require.Equal(t, 6, height)
+			require.NoError(t, result.Release())
+		}
+	})
+
+	t.Run("ParquetIncludeFilePaths", func(t *testing.T) {
+		result, err := ReadParquetWithOptions(filepath.Join(dir, "*.parquet"), ParquetOptions{
+			IncludeFilePaths: "file",
+		}).Filter(Col("file").Eq(Lit(filepath.Join(dir, "b.parquet")))).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 3, height)
+	})
+
+	t.Run("CSVIncludeFilePaths", func(t *testing.T) {
+		result, err := ReadCSVWithOptions("../testdata/small.csv", CSVOptions{
+			HasHeader:        true,
+			IncludeFilePaths: "file",
+		}).Select("id", "file").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		out, err := result.ToCsv()
+		require.NoError(t, err)
+		require.Equal(t, "id,file\n1,../testdata/small.csv\n2,../testdata/small.csv\n3,../testdata/small.csv\n", out)
+	})
+}
+
+// TestExplain inspects query plans and the pending operation queue
+func TestExplain(t *testing.T) {
+	newQuery := func() *DataFrame {
+		return ReadParquet("../testdata/fortune1000_2024.parquet").
+			Filter(Col("Rank").Lt(Lit(4))).
+			Select("Company")
+	}
+
+	t.Run("OptimizedPlanPushesFilterIntoScan", func(t *testing.T) {
+		df := newQuery()
+		plan, err := df.Explain(true)
+		require.NoError(t, err)
+		require.Contains(t, plan, "Parquet SCAN")
+		require.Contains(t, plan, "SELECTION")
+		require.NotContains(t, plan, "FILTER")
+
+		// Explain leaves the queue intact
+		result, err := df.Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 3, height)
+	})
+
+	t.Run("UnoptimizedPlan", func(t *testing.T) {
+		plan, err := newQuery().Explain(false)
+		require.NoError(t, err)
+		require.Contains(t, plan, "FILTER")
+	})
+
+	t.Run("DescribeOps", func(t *testing.T) {
+		require.Equal(t, "0: ReadParquet\n1: FilterExpr\n2: ExprSql\n3: SelectExpr\n", newQuery().DescribeOps())
+
+		withError := ReadCSVWithOptions("../testdata/small.csv", CSVOptions{NRows: -1})
+		require.Contains(t, withError.DescribeOps(), "1: Error: ReadCSV: ")
+
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		require.Equal(t, "(executed, no pending operations)", result.DescribeOps())
+	})
+
+	t.Run("ExplainErrors", func(t *testing.T) {
+		_, err := ReadCSVWithOptions("../testdata/small.csv", CSVOptions{NRows: -1}).Explain(true)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot be negative")
+
+		_, err = ReadCSV("../testdata/small.csv").Select("missing").Explain(true)
+		require.Error(t, err)
+	})
+}
+
+// TestErrorTaxonomy checks sentinel errors and builder call-site attribution
+func TestErrorTaxonomy(t *testing.T) {
+	t.Run("ColumnNotFound", func(t *testing.T) {
+		_, err := ReadCSV("../testdata/sample.csv").Select("missing").Collect()
+		require.ErrorIs(t, err, ErrColumnNotFound)
+		require.NotErrorIs(t, err, ErrSchemaMismatch)
+	})
+
+	t.Run("LazyErrorPointsAtBuilder", func(t *testing.T) {
+		// Lazy plans fail at Collect; the error is traced back to the Select that caused it
+		df := ReadCSV("../testdata/sample.csv").
+			Filter(Col("salary").Gt(Lit(1000))).
+			Select("missing")
+		_, err := df.Collect()
+
+		var polarsErr *Error
+		require.ErrorAs(t, err, &polarsErr)
+		require.Equal(t, 3, polarsErr.Frame) // 0: ReadCsv, 1: FilterExpr, 2: Expr, 3: SelectExpr
+		require.Equal(t, "Select", polarsErr.Builder)
+		require.Contains(t, polarsErr.Caller, "dataframe_test.go:")
+		require.Contains(t, err.Error(), "(Select at dataframe_test.go:")
+	})
+
+	t.Run("GoValidationError", func(t *testing.T) {
+		_, err := ReadCSV("../testdata/sample.csv").Limit(0).Collect()
+		require.ErrorIs(t, err, ErrInvalidOperation)
+
+		var polarsErr *Error
+		require.ErrorAs(t, err, &polarsErr)
+		require.Equal(t, 1, polarsErr.Frame)
+		require.Equal(t, "Limit", polarsErr.Builder)
+	})
+
+	t.Run("ContextMisuse", func(t *testing.T) {
+		_, err := ReadCSV("../testdata/sample.csv").Agg(Col("salary").Mean()).Collect()
+		require.ErrorIs(t, err, ErrInvalidOperation)
+
+		var polarsErr *Error
+		require.ErrorAs(t, err, &polarsErr)
+		require.Equal(t, "Agg", polarsErr.Builder)
+	})
+
+	t.Run("IO", func(t *testing.T) {
+		_, err := ReadParquet("../testdata/nonexistent.parquet").Collect()
+		require.ErrorIs(t, err, ErrIO)
+	})
+
+	t.Run("WrapsGoCause", func(t *testing.T) {
+		_, err := ReadCSVFrom(iotest.ErrReader(io.ErrUnexpectedEOF), CSVOptions{}).Collect()
+		require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		require.ErrorIs(t, err, ErrInvalidOperation)
+
+		var polarsErr *Error
+		require.ErrorAs(t, err, &polarsErr)
+		require.Equal(t, "ReadCSVFrom", polarsErr.Builder)
+	})
+}
+
+// TestPanicRecovery checks that a Rust panic comes back as ErrPanic instead of aborting
+func TestPanicRecovery(t *testing.T) {
+	_, err := ReadCSV("../testdata/sample.csv").raisePanicForTesting().Collect()
+	require.ErrorIs(t, err, ErrPanic)
+
+	var polarsErr *Error
+	require.ErrorAs(t, err, &polarsErr)
+	require.Equal(t, 1, polarsErr.Frame)
+	require.Equal(t, "panic raised for testing", polarsErr.Message)
+
+	// The process and the library keep working afterwards
+	result, err := ReadCSV("../testdata/sample.csv").Collect()
+	require.NoError(t, err)
+	defer result.Release()
+	height, err := result.Height()
+	require.NoError(t, err)
+	require.Positive(t, height)
+}
+
+// TestCollectContext checks context cancellation of queries and writers
+func TestCollectContext(t *testing.T) {
+	expected := "id,value\n1,100\n2,200\n3,300\n"
+
+	t.Run("CancellableContext", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+
+		result, err := ReadCSV("../testdata/small.csv").CollectContext(ctx)
+		require.NoError(t, err)
+		defer result.Release()
+
+		csv, err := result.ToCsv()
+		require.NoError(t, err)
+		require.Equal(t, expected, csv)
+	})
+
+	t.Run("AlreadyCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		df := ReadCSV("../testdata/small.csv")
+		_, err := df.CollectContext(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+
+		// Nothing ran, so the query can still be collected
+		result, err := df.Collect()
+		require.NoError(t, err)
+		defer result.Release()
+	})
+
+	t.Run("DeadlineWhileRunning", func(t *testing.T) {
+		// 1000 x 1000 x 20 row cross join plus a sort outlasts the deadline
+		ranks := func(name string) *DataFrame {
+			return ReadParquet("../testdata/fortune1000_2024.parquet").Select(Col("Rank").Alias(name))
+		}
+		query := ranks("a").
+			CrossJoin(ranks("b")).
+			CrossJoin(ranks("c").Limit(20)).
+			SortBy([]SortField{Desc("a"), Asc("b"), Desc("c")})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := query.CollectContext(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 2*time.Second)
+
+		// The library keeps working after an interrupted query
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+	})
+
+	t.Run("DeadlineWhileDescribing", func(t *testing.T) {
+		// Percentiles over a 20 million row cross join outlast the deadline
+		ranks := func(name string) *DataFrame {
+			return ReadParquet("../testdata/fortune1000_2024.parquet").Select(Col("Rank").Alias(name))
+		}
+		query := ranks("a").
+			CrossJoin(ranks("b")).
+			CrossJoin(ranks("c").Limit(20)).
+			Describe(0.1, 0.5, 0.9)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err := query.CollectContext(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 2*time.Second)
+	})
+
+	t.Run("Writers", func(t *testing.T) {
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		var buf bytes.Buffer
+		require.NoError(t, result.WriteCSVToContext(context.Background(), &buf))
+		require.Equal(t, expected, buf.String())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		buf.Reset()
+		require.ErrorIs(t, result.WriteCSVToContext(ctx, &buf), context.Canceled)
+		require.ErrorIs(t, result.WriteParquetToContext(ctx, &buf), context.Canceled)
+		require.ErrorIs(t, result.WriteIPCStreamContext(ctx, &buf, IPCCompressionNone), context.Canceled)
+		require.Zero(t, buf.Len())
+	})
+
+	t.Run("DeadlineWhileEncoding", func(t *testing.T) {
+		// A million row cross join takes far longer to encode as CSV than the deadline
+		ranks := func(name string) *DataFrame {
+			return ReadParquet("../testdata/fortune1000_2024.parquet").Select(Col("Rank").Alias(name))
+		}
+		result, err := ranks("a").CrossJoin(ranks("b")).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+
+		var buf bytes.Buffer
+		require.ErrorIs(t, result.WriteCSVToContext(ctx, &buf), context.DeadlineExceeded)
+		require.Zero(t, buf.Len())
+
+		// The DataFrame can still be written in full afterwards
+		require.NoError(t, result.WriteCSVTo(&buf))
+		require.True(t, strings.HasPrefix(buf.String(), "a,b\n"))
+	})
+}
+
+// TestHandleTracking checks the live handle registry and automatic cleanup
+func TestHandleTracking(t *testing.T) {
+	isLive := func(id uint64) bool {
+		return slices.ContainsFunc(LiveHandles(), func(h LiveHandle) bool { return h.ID == id })
+	}
+
+	t.Run("ReleaseUnregisters", func(t *testing.T) {
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		id := result.ref.id
+		require.True(t, isLive(id))
+
+		require.NoError(t, result.Release())
+		require.False(t, isLive(id))
+		require.NoError(t, result.Release()) // Releasing twice is a no-op
+	})
+
+	t.Run("CollectAgainReleasesOldHandle", func(t *testing.T) {
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		first := result.ref.id
+
+		result, err = result.Select("id").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		require.False(t, isLive(first))
+		require.True(t, isLive(result.ref.id))
+	})
+
+	t.Run("DebugStacks", func(t *testing.T) {
+		SetHandleDebug(true)
+		defer SetHandleDebug(false)
+
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		handles := LiveHandles()
+		idx := slices.IndexFunc(handles, func(h LiveHandle) bool { return h.ID == result.ref.id })
+		require.NotEqual(t, -1, idx)
+		require.Contains(t, handles[idx].Stack, "TestHandleTracking")
+	})
+
+	t.Run("GarbageCollectorReleases", func(t *testing.T) {
+		id := func() uint64 {
+			result, err := ReadCSV("../testdata/small.csv").Collect()
+			require.NoError(t, err)
+			return result.ref.id // result becomes unreachable here
+		}()
+
+		require.Eventually(t, func() bool {
+			runtime.GC()
+			return !isLive(id)
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+	t.Run("AccessorsDuringGarbageCollection", func(t *testing.T) {
+		// Each result is unreachable as soon as its accessor is called, so the collector
+		// may run its cleanup while Rust still reads the handle
+		collect := func() *DataFrame {
+			result, err := ReadCSV("../testdata/small.csv").Collect()
+			require.NoError(t, err)
+			return result
+		}
+
+		stop := make(chan struct{})
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+					runtime.GC()
+				}
+			}
+		}()
+		defer func() {
+			close(stop)
+			wg.Wait()
+		}()
+
+		for range 200 {
+			height, err := collect().Height()
+			require.NoError(t, err)
+			require.Equal(t, 3, height)
+
+			csv, err := collect().ToCsv()
+			require.NoError(t, err)
+			require.Equal(t, "id,value\n1,100\n2,200\n3,300\n", csv)
+		}
+	})
+}
+
+// maxLiveID returns the highest live handle ID. IDs only grow, so every handle created
+// after the call has a higher one
+func maxLiveID() uint64 {
+	var id uint64
+	for _, h := range LiveHandles() {
+		id = max(id, h.ID)
+	}
+	return id
+}
+
+// handlesAfter returns the live handles created after the one with the given ID. Earlier
+// handles may be released by the garbage collector at any time, so tests count only these
+func handlesAfter(id uint64) []LiveHandle {
+	return slices.DeleteFunc(LiveHandles(), func(h LiveHandle) bool { return h.ID <= id })
+}
+
+// TestConcurrentExecution shares one collected DataFrame between goroutines; run with -race
+func TestConcurrentExecution(t *testing.T) {
+	const workers = 16
+
+	collectDimension := func(t *testing.T) *DataFrame {
+		dim, err := ReadCSV("../testdata/sample.csv").
+			Select("name", "department").
+			Filter(Col("department").Eq(Lit("Engineering"))).
+			Collect()
+		require.NoError(t, err)
+		return dim
+	}
+	t.Run("ReleaseDuringCollect", func(t *testing.T) {
+		before := maxLiveID()
+		dim := collectDimension(t)
+
+		// Every join embeds its own reference before dim is released
+		joins := make([]*DataFrame, workers)
+		for i := range joins {
+			joins[i] = ReadCSV("../testdata/sample.csv").InnerJoin(dim, "name")
+		}
+
+		var wg sync.WaitGroup
+		heights := make([]int, workers)
+		errs := make([]error, workers+1)
+		for i, join := range joins {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := join.Collect()
+				if err != nil {
+					errs[i] = err
+					return
+				}
+				heights[i], errs[i] = result.Height()
+				errs[i] = errors.Join(errs[i], result.Release())
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[workers] = dim.Release()
+		}()
+		wg.Wait()
+
+		for i := range joins {
+			require.NoError(t, errs[i])
+			require.Equal(t, 3, heights[i])
+		}
+		require.NoError(t, errs[workers])
+		require.Empty(t, handlesAfter(before)) // Join references were released by Collect
+	})
+
+	t.Run("ReleaseDuringJoin", func(t *testing.T) {
+		dim := collectDimension(t)
+
+		var wg sync.WaitGroup
+		errs := make([]error, workers)
+		for i := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				result, err := ReadCSV("../testdata/sample.csv").InnerJoin(dim, "name").Collect()
+				if err != nil {
+					errs[i] = err // Joined after the release
+					return
+				}
+				height, err := result.Height()
+				if err == nil && height != 3 {
+					err = fmt.Errorf("joined %d rows, want 3", height)
+				}
+				errs[i] = errors.Join(err, result.Release())
+			}()
+		}
+		require.NoError(t, dim.Release())
+		wg.Wait()
+
+		for _, err := range errs {
+			if err != nil {
+				require.ErrorIs(t, err, ErrInvalidOperation)
+				require.ErrorContains(t, err, "released")
+			}
+		}
+	})
+
+	t.Run("InputsChangedBeforeCollect", func(t *testing.T) {
+		dim := collectDimension(t)
+		join := ReadCSV("../testdata/sample.csv").Select("name", "age").InnerJoin(dim, "name")
+		concat := Concat(dim, ReadCSV("../testdata/sample.csv").Select("name", "department"))
+
+		// Re-collecting dim swaps and releases the handle both plans were built with,
+		// then releasing it drops the last caller reference
+		_, err := dim.Limit(1).Collect()
+		require.NoError(t, err)
+		require.NoError(t, dim.Release())
+
+		result, err := join.Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 3, height)
+
+		result, err = concat.Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		height, err = result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 10, height)
+	})
+
+	t.Run("QueryKeepsSourceHandle", func(t *testing.T) {
+		source := collectDimension(t)
+		defer source.Release()
+
+		result, err := source.Query("SELECT name FROM df").Collect()
+		require.NoError(t, err)
+		require.NoError(t, result.Release())
+
+		height, err := source.Height()
+		require.NoError(t, err)
+		require.Equal(t, 3, height)
+	})
+}
+
+// TestMapBatches runs Go functions inside the lazy plan
+func TestMapBatches(t *testing.T) {
+	t.Run("WholeColumn", func(t *testing.T) {
+		double := func(s Series) (Series, error) {
+			values := s.Int64s()
+			doubled := make([]int64, len(values))
+			for i, v := range values {
+				doubled[i] = v * 2
+			}
+			return NewSeries(s.Name(), doubled), nil
+		}
+
+		result, err := ReadCSV("../testdata/small.csv").
+			WithColumns(Col("value").MapBatches(double, Int64).Alias("doubled")).
+			Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (3, 3)
+┌─────┬───────┬─────────┐
+│ id  ┆ value ┆ doubled │
+│ --- ┆ ---   ┆ ---     │
+│ i64 ┆ i64   ┆ i64     │
+╞═════╪═══════╪═════════╡
+│ 1   ┆ 100   ┆ 200     │
+│ 2   ┆ 200   ┆ 400     │
+│ 3   ┆ 300   ┆ 600     │
+└─────┴───────┴─────────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("MapElementsKeepsNulls", func(t *testing.T) {
+		upper := func(v any) (any, error) {
+			return strings.ToUpper(v.(string)), nil
+		}
+
+		result, err := FromColumns(map[string][]any{"name": {"alice", nil, "carol"}}).
+			Select("name", Col("name").MapElements(upper, String).Alias("upper")).
+			Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (3, 2)
+┌───────┬───────┐
+│ name  ┆ upper │
+│ ---   ┆ ---   │
+│ str   ┆ str   │
+╞═══════╪═══════╡
+│ alice ┆ ALICE │
+│ null  ┆ null  │
+│ carol ┆ CAROL │
+└───────┴───────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("MapElementsStringsOutliveBatch", func(t *testing.T) {
+		var seen []string
+		remember := func(v any) (any, error) {
+			seen = append(seen, v.(string))
+			return int64(len(v.(string))), nil
+		}
+
+		result, err := FromColumns(map[string][]any{"name": {"alice", nil, "carol"}}).
+			Select(Col("name").MapElements(remember, Int64)).
+			Collect()
+		require.NoError(t, err)
+		require.NoError(t, result.Release())
+		require.Equal(t, []string{"alice", "carol"}, seen) // Copied out of the Rust buffer
+	})
+
+	t.Run("NullableNumericBatch", func(t *testing.T) {
+		increment := func(s Series) (Series, error) {
+			values := s.Int64s()
+			incremented := make([]int64, len(values))
+			valid := make([]bool, len(values))
+			for i, v := range values {
+				if valid[i] = !s.IsNull(i); valid[i] {
+					incremented[i] = v + 1
+				}
+			}
+			return NewSeries(s.Name(), incremented).WithNulls(valid), nil
+		}
+
+		result, err := FromColumns(map[string][]any{"score": {int64(10), nil, int64(30)}}).
+			Select(Col("score").MapBatches(increment, Int64)).
+			Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (3, 1)
+┌───────┐
+│ score │
+│ ---   │
+│ i64   │
+╞═══════╡
+│ 11    │
+│ null  │
+│ 31    │
+└───────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("InsideAgg", func(t *testing.T) {
+		thousands := func(v any) (any, error) {
+			return v.(int64) / 1000, nil
+		}
+
+		result, err := ReadCSV("../testdata/sample.csv").
+			GroupBy("department").
+			Agg(Col("salary").MapElements(thousands, Int64).Sum().Alias("salary_k")).
+			Sort([]string{"department"}).
+			Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (3, 2)
+┌─────────────┬──────────┐
+│ department  ┆ salary_k │
+│ ---         ┆ ---      │
+│ str         ┆ i64      │
+╞═════════════╪══════════╡
+│ Engineering ┆ 185      │
+│ Marketing   ┆ 118      │
+│ Sales       ┆ 107      │
+└─────────────┴──────────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("CastsToReturnType", func(t *testing.T) {
+		hundreds := func(v any) (any, error) {
+			return v.(int64) / 100, nil
+		}
+
+		result, err := ReadCSV("../testdata/small.csv").
+			Select(Col("value").MapElements(hundreds, Int32)).
+			Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (3, 1)
+┌───────┐
+│ value │
+│ ---   │
+│ i32   │
+╞═══════╡
+│ 1     │
+│ 2     │
+│ 3     │
+└───────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("ErrorFailsPlan", func(t *testing.T) {
+		failing := func(s Series) (Series, error) {
+			return Series{}, errors.New("rates service unavailable")
+		}
+
+		_, err := ReadCSV("../testdata/small.csv").
+			Select(Col("value").MapBatches(failing, Float64)).
+			Collect()
+		require.ErrorIs(t, err, ErrComputeError)
+		require.ErrorContains(t, err, "rates service unavailable")
+	})
+
+	t.Run("PanicFailsPlan", func(t *testing.T) {
+		panicking := func(v any) (any, error) {
+			panic("lookup table missing")
+		}
+
+		_, err := ReadCSV("../testdata/small.csv").
+			Select(Col("value").MapElements(panicking, Int64)).
+			Collect()
+		require.ErrorIs(t, err, ErrComputeError)
+		require.ErrorContains(t, err, "panic: lookup table missing")
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		_, err := ReadCSV("../testdata/small.csv").
+			Select(Col("value").MapBatches(nil, Int64)).
+			Collect()
+		require.ErrorContains(t, err, "MapBatches() function cannot be nil")
+
+		_, err = ReadCSV("../testdata/small.csv").
+			Select(Col("value").MapElements(func(v any) (any, error) { return v, nil }, Date)).
+			Collect()
+		require.ErrorContains(t, err, "MapElements() unsupported return type")
+	})
+}
+
+// TestMemoryStats checks size reporting and thread pool configuration
+func TestMemoryStats(t *testing.T) {
+	t.Run("EstimatedSize", func(t *testing.T) {
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		size, err := result.EstimatedSize()
+		require.NoError(t, err)
+		require.GreaterOrEqual(t, size, 2*3*8) // Two i64 columns of three rows
+
+		_, err = ReadCSV("../testdata/small.csv").EstimatedSize()
+		require.ErrorContains(t, err, "must be executed")
+	})
+
+	t.Run("StatsTracksHandles", func(t *testing.T) {
+		before := maxLiveID()
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		size, err := result.EstimatedSize()
+		require.NoError(t, err)
+
+		held := Stats()
+		require.Len(t, handlesAfter(before), 1)
+		require.GreaterOrEqual(t, held.LiveHandles, 1)
+		require.GreaterOrEqual(t, held.DataFrames, 1)
+		require.GreaterOrEqual(t, held.Bytes, int64(size))
+
+		// A second reference to the same DataFrame is not counted twice. Cleanups of
+		// earlier results may still run, so the totals can only stay or drop
+		pending := ReadCSV("../testdata/small.csv").CrossJoin(result)
+		shared := Stats()
+		require.Len(t, handlesAfter(before), 2)
+		require.LessOrEqual(t, shared.DataFrames, held.DataFrames)
+		require.LessOrEqual(t, shared.Bytes, held.Bytes)
+
+		require.NoError(t, result.Release())
+		require.Len(t, handlesAfter(before), 1) // Still held by the pending join
+		runtime.KeepAlive(pending)
+	})
+
+	t.Run("ThreadPoolSize", func(t *testing.T) {
+		size := ThreadPoolSize()
+		require.Positive(t, size)
+
+		require.NoError(t, SetThreadPoolSize(size)) // Same size as the running pool
+		require.Equal(t, strconv.Itoa(size), os.Getenv("POLARS_MAX_THREADS"))
+		err := SetThreadPoolSize(size + 1)
+		require.ErrorIs(t, err, ErrInvalidOperation)
+		require.ErrorContains(t, err, "already started")
+		require.Equal(t, strconv.Itoa(size), os.Getenv("POLARS_MAX_THREADS")) // Restored
+		require.Error(t, SetThreadPoolSize(0))
+	})
+}
+
+// TestSetThreadPoolSize sizes the pool before first use, in a child process because the
+// pool of the test process is already running
+func TestSetThreadPoolSize(t *testing.T) {
+	if os.Getenv("FIRN_THREAD_POOL_CHILD") == "1" {
+		require.NoError(t, SetThreadPoolSize(3))
+		require.Equal(t, 3, ThreadPoolSize())
+		require.Equal(t, "3", os.Getenv("POLARS_MAX_THREADS")) // Set from Go, so Go sees it
+
+		result, err := ReadCSV("../testdata/small.csv").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		require.Equal(t, 3, ThreadPoolSize())
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestSetThreadPoolSize$", "-test.v")
+	cmd.Env = append(os.Environ(), "FIRN_THREAD_POOL_CHILD=1")
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, "%s", output)
+	require.Contains(t, string(output), "--- PASS: TestSetThreadPoolSize")
+}
+
+// TestSQLContext queries several registered tables at once
+func TestSQLContext(t *testing.T) {
+	departments := func() *DataFrame {
+		return FromColumns(map[string][]any{
+			"department": {"Engineering", "Marketing", "Sales"},
+			"floor":      {int64(3), int64(2), int64(1)},
+		})
+	}
+
+	t.Run("JoinsLazyTables", func(t *testing.T) {
+		ctx := NewSQLContext()
+		defer ctx.Release()
+		require.NoError(t, ctx.Register("employees", ReadCSV("../testdata/sample.csv")))
+		require.NoError(t, ctx.Register("departments", departments()))
+
+		result, err := ctx.Execute(`
+			SELECT e.name, d.floor
+			FROM employees e JOIN departments d ON e.department = d.department
+			WHERE e.age > 30
+			ORDER BY e.name`).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (2, 2)
+┌─────────┬───────┐
+│ name    ┆ floor │
+│ ---     ┆ ---   │
+│ str     ┆ i64   │
+╞═════════╪═══════╡
+│ Charlie ┆ 3     │
+│ Eve     ┆ 3     │
+└─────────┴───────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("TablesAndUnregister", func(t *testing.T) {
+		ctx := NewSQLContext()
+		defer ctx.Release()
+		require.NoError(t, ctx.Register("users", ReadCSV("../testdata/sample.csv")))
+		require.NoError(t, ctx.Register("departments", departments()))
+		require.Equal(t, []string{"departments", "users"}, ctx.Tables())
+
+		ctx.Unregister("departments")
+		require.Equal(t, []string{"users"}, ctx.Tables())
+
+		_, err := ctx.Execute("SELECT * FROM departments").Collect()
+		require.ErrorContains(t, err, "departments")
+	})
+
+	t.Run("QueryOutlivesRegistration", func(t *testing.T) {
+		employees, err := ReadCSV("../testdata/sample.csv").Collect()
+		require.NoError(t, err)
+
+		ctx := NewSQLContext()
+		require.NoError(t, ctx.Register("employees", employees))
+		query := ctx.Execute("SELECT COUNT(*) AS n FROM employees")
+
+		// Neither the context nor the registered DataFrame owns the query's inputs
+		ctx.Release()
+		require.NoError(t, employees.Release())
+
+		result, err := query.Collect()
+		require.NoError(t, err)
+		defer result.Release()
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 1, height)
+	})
+
+	t.Run("InvalidRegistration", func(t *testing.T) {
+		ctx := NewSQLContext()
+		require.ErrorContains(t, ctx.Register("", ReadCSV("../testdata/sample.csv")), "name cannot be empty")
+		require.ErrorContains(t, ctx.Register("t", nil), "cannot be nil")
+		require.ErrorContains(t, ctx.Register("t", NewDataFrame().Limit(-1)), "Limit() requires n > 0")
+		require.Empty(t, ctx.Tables())
+	})
+}
+
+func TestQueryParams(t *testing.T) {
+	t.Run("NumberedPlaceholders", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.QueryParams(
+			"SELECT name, salary FROM df WHERE department = $1 AND salary > $2 ORDER BY salary DESC",
+			"Engineering", 60000,
+		).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (2, 2)
+┌─────────┬────────┐
+│ name    ┆ salary │
+│ ---     ┆ ---    │
+│ str     ┆ i64    │
+╞═════════╪════════╡
+│ Charlie ┆ 70000  │
+│ Eve     ┆ 65000  │
+└─────────┴────────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("PositionalPlaceholders", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.QueryParams(
+			"SELECT name FROM df WHERE department = ? AND age < ? AND salary > ?",
+			"Sales", int32(28), 50000.5,
+		).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (1, 1)
+┌───────┐
+│ name  │
+│ ---   │
+│ str   │
+╞═══════╡
+│ Grace │
+└───────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("StringsCannotInject", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+		result, err := df.QueryParams("SELECT name FROM df WHERE name = $1", "x' OR '1'='1").Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		height, err := result.Height()
+		require.NoError(t, err)
+		require.Equal(t, 0, height)
+	})
+
+	t.Run("PayloadsStayStrings", func(t *testing.T) {
+		for _, payload := range []string{
+			`\' OR 1=1 --`,
+			`x' -- comment`,
+			`x' /* comment */ OR '1'='1`,
+			`'; DROP TABLE df; SELECT * FROM df WHERE '1'='1`,
+			`$1 ? \\ ''`,
+		} {
+			// Matches no name, so an injected condition would show up as rows
+			filtered, err := ReadCSV("../testdata/sample.csv").
+				QueryParams("SELECT name FROM df WHERE name = $1", payload).Collect()
+			require.NoError(t, err, payload)
+			height, err := filtered.Height()
+			require.NoError(t, err)
+			require.Zero(t, height, payload)
+			require.NoError(t, filtered.Release())
+
+			// The value arrives byte for byte
+			selected, err := ReadCSV("../testdata/sample.csv").
+				QueryParams("SELECT name, ? AS v FROM df LIMIT 1", payload).Collect()
+			require.NoError(t, err, payload)
+			csv, err := selected.ToCsv()
+			require.NoError(t, err)
+			require.Equal(t, "name,v\nAlice,"+payload+"\n", csv)
+			require.NoError(t, selected.Release())
+		}
+	})
+
+	t.Run("InvalidArguments", func(t *testing.T) {
+		df := ReadCSV("../testdata/sample.csv")
+
+		_, err := df.QueryParams("SELECT * FROM df WHERE age > $1", struct{}{}).Collect()
+		require.ErrorContains(t, err, "argument 1 has unsupported type struct {}")
+
+		// The failed call leaves df untouched
+		result, err := df.Collect()
+		require.NoError(t, err)
+		require.NoError(t, result.Release())
+		df = ReadCSV("../testdata/sample.csv")
+
+		_, err = df.QueryParams("SELECT * FROM df WHERE age > $1; SELECT * FROM df", 30).Collect()
+		require.ErrorContains(t, err, "must be one statement")
+
+		_, err = df.QueryParams("SELECT * FROM df WHERE age > $1 AND salary > $2", 30).Collect()
+		require.ErrorIs(t, err, ErrInvalidOperation)
+		require.ErrorContains(t, err, "placeholder $2 has no parameter")
+
+		_, err = df.QueryParams("SELECT * FROM df WHERE age > $1", 30, 40).Collect()
+		require.ErrorContains(t, err, "parameter $2 is not used")
+
+		_, err = df.QueryParams("SELECT * FROM df WHERE age > ? AND salary > $1", 30).Collect()
+		require.ErrorContains(t, err, "cannot mix")
+	})
+}
+
+func TestMarshalPlan(t *testing.T) {
+	newQuery := func() *DataFrame {
+		return ReadCSV("../testdata/sample.csv").
+			Filter(Col("age").Gt(Lit(30))).
+			Select("name", "age")
+	}
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		data, err := newQuery().MarshalPlan()
+		require.NoError(t, err)
+
+		var envelope map[string]any
+		require.NoError(t, json.Unmarshal(data, &envelope))
+		require.Equal(t, "firn.plan", envelope["format"])
+		require.Equal(t, float64(1), envelope["version"])
+		require.NotEmpty(t, envelope["polars"])
+
+		df, err := UnmarshalPlan(data)
+		require.NoError(t, err)
+		result, err := df.SortBy([]SortField{Asc("name")}).Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (2, 2)
+┌─────────┬─────┐
+│ name    ┆ age │
+│ ---     ┆ --- │
+│ str     ┆ i64 │
+╞═════════╪═════╡
+│ Charlie ┆ 35  │
+│ Eve     ┆ 32  │
+└─────────┴─────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("EmbedsCollectedData", func(t *testing.T) {
+		source, err := FromColumns(map[string][]any{
+			"department": {"Engineering", "Sales"},
+			"floor":      {int64(3), int64(1)},
+		}).Collect()
+		require.NoError(t, err)
+
+		data, err := source.Filter(Col("floor").Gt(Lit(2))).MarshalPlan()
+		require.NoError(t, err)
+		require.NoError(t, source.Release()) // The plan carries its own copy
+
+		df, err := UnmarshalPlan(data)
+		require.NoError(t, err)
+		result, err := df.Collect()
+		require.NoError(t, err)
+		defer result.Release()
+
+		expected := `shape: (1, 2)
+┌─────────────┬───────┐
+│ department  ┆ floor │
+│ ---         ┆ ---   │
+│ str         ┆ i64   │
+╞═════════════╪═══════╡
+│ Engineering ┆ 3     │
+└─────────────┴───────┘`
+		require.Equal(t, expected, result.String())
+	})
+
+	t.Run("RejectsForeignPlans", func(t *testing.T) {
+		data, err := newQuery().MarshalPlan()
+		require.NoError(t, err)
+		var envelope map[string]any
+		require.NoError(t, json.Unmarshal(data, &envelope))
+		rewrite := func(key string, value any) []byte {
+			changed := maps.Clone(envelope)
+			changed[key] = value
+			data, err := json.Marshal(changed)
+			require.NoError(t, err)
+			return data
+		}
+
+		_, err = UnmarshalPlan([]byte("not json"))
+		require.ErrorContains(t, err, "UnmarshalPlan")
+		_, err = UnmarshalPlan(rewrite("format", "other"))
+		require.ErrorContains(t, err, "not a serialized plan")
+		_, err = UnmarshalPlan(rewrite("version", 2))
+		require.ErrorContains(t, err, "unsupported plan version 2")
+		_, err = UnmarshalPlan(rewrite("polars", "0.1"))
+		require.ErrorContains(t, err, "serialized with Polars 0.1")
+
+		df, err := UnmarshalPlan(rewrite("plan", map[string]any{"bogus": true}))
+		require.NoError(t, err)
+		_, err = df.Collect()
+		require.ErrorIs(t, err, ErrInvalidOperation)
+		require.ErrorContains(t, err, "Invalid plan")
+	})
+
+	t.Run("GoFunctionsCannotBeSerialized", func(t *testing.T) {
+		identity := func(s Series) (Series, error) { return s, nil }
+		_, err := newQuery().Select(Col("age").MapBatches(identity, Int64)).MarshalPlan()
+		require.Error(t, err)
+	})
+
+	t.Run("CredentialsAreNotSerialized", func(t *testing.T) {
+		cloud := &CloudOptions{
+			Endpoint:    "http://localhost:9000",
+			Credentials: CloudCredentials{AccessKeyID: "key", SecretAccessKey: "secret"},
+			AllowHTTP:   true,
+		}
+		scan := ReadParquetWithOptions("s3://bucket/data.parquet", ParquetOptions{Cloud: cloud})
+		_, err := scan.MarshalPlan()
+		require.ErrorContains(t, err, "static cloud credentials")
+
+		// Also when the scan is embedded in another plan
+		_, err = newQuery().CrossJoin(scan).MarshalPlan()
+		require.ErrorContains(t, err, "static cloud credentials")
+		_, err = Concat(newQuery(), ReadCSVWithOptions("s3://bucket/data.csv", CSVOptions{Cloud: cloud})).MarshalPlan()
+		require.ErrorContains(t, err, "static cloud credentials")
+	})
 }
 
 // TestConditionalExpressions demonstrates When/Then/Otherwise functionality
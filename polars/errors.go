@@ -0,0 +1,180 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// Sentinel errors for errors.Is; an *Error unwraps to the one matching its Code
+// Example: if errors.Is(err, polars.ErrColumnNotFound) { ... }
+var (
+	ErrColumnNotFound   = errors.New("column not found")
+	ErrSchemaMismatch   = errors.New("schema mismatch")
+	ErrComputeError     = errors.New("compute error")
+	ErrInvalidOperation = errors.New("invalid operation")
+	ErrIO               = errors.New("i/o error")
+
+	// ErrPanic reports a panic inside Polars, caught at the FFI boundary; Error.Message
+	// holds the panic message. The plan's intermediate results are leaked.
+	ErrPanic = errors.New("polars panicked")
+)
+
+// Error codes matching the ERROR_* constants in rust/src/lib.rs
+const (
+	codeNullHandle       = 1
+	codeNullArgs         = 2
+	codeInvalidUTF8      = 3
+	codePolarsOperation  = 4 // Any Polars error without a more specific code
+	codeColumnNotFound   = 5
+	codeSchemaMismatch   = 6
+	codeCompute          = 7
+	codeInvalidOperation = 8
+	codeIO               = 9
+	codePanic            = 10 // A Rust panic caught at the FFI boundary
+	codeCancelled        = 11 // Stopped by CollectContext; surfaced as ctx.Err()
+)
+
+// Error represents a Polars operation error
+type Error struct {
+	Code    int
+	Message string
+	Frame   int    // Index of the failing operation in the queue, as listed by DescribeOps
+	Builder string // Builder that queued the failing operation, e.g. "Filter"
+	Caller  string // file:line of that builder call
+	cause   error  // Go-side validation error, if any
+}
+
+func (e *Error) Error() string {
+	if e.Builder != "" {
+		return fmt.Sprintf("polars error %d at operation %d (%s at %s): %s", e.Code, e.Frame, e.Builder, e.Caller, e.Message)
+	}
+	if e.Frame > 0 {
+		return fmt.Sprintf("polars error %d at operation %d: %s", e.Code, e.Frame, e.Message)
+	}
+	return fmt.Sprintf("polars error %d: %s", e.Code, e.Message)
+}
+
+// Unwrap exposes the sentinel for e.Code and the underlying Go error, if any
+func (e *Error) Unwrap() []error {
+	var errs []error
+	if sentinel := sentinelForCode(e.Code); sentinel != nil {
+		errs = append(errs, sentinel)
+	}
+	if e.cause != nil {
+		errs = append(errs, e.cause)
+	}
+	return errs
+}
+
+// sentinelForCode maps an FFI error code to its sentinel; generic Polars errors have none
+func sentinelForCode(code int) error {
+	switch code {
+	case codeColumnNotFound:
+		return ErrColumnNotFound
+	case codeSchemaMismatch:
+		return ErrSchemaMismatch
+	case codeCompute:
+		return ErrComputeError
+	case codeNullHandle, codeNullArgs, codeInvalidUTF8, codeInvalidOperation:
+		return ErrInvalidOperation
+	case codeIO:
+		return ErrIO
+	case codePanic:
+		return ErrPanic
+	default:
+		return nil
+	}
+}
+
+// ffiError builds an *Error from an FFI function that reports failures through
+// an error code and a Rust-allocated message (freed here) instead of FfiResult
+func ffiError(code C.int, message *C.char, fallback string) error {
+	if message != nil {
+		fallback = C.GoString(message)
+		C.free_string(message)
+	}
+	return &Error{Code: int(code), Message: fallback}
+}
+
+// callSite is the user code location of the builder call that queued an operation
+type callSite struct {
+	builder string // Exported builder name, e.g. "Filter"
+	file    string
+	line    int
+}
+
+// attribute fills in the builder and caller of err from the operation at err.Frame
+func (s *callSite) attribute(err *Error) {
+	if s == nil {
+		return
+	}
+	err.Builder = s.builder
+	err.Caller = fmt.Sprintf("%s:%d", s.file, s.line)
+}
+
+// packagePrefix prefixes the runtime names of every function in this package
+var packagePrefix = reflect.TypeOf(DataFrame{}).PkgPath() + "."
+
+// captureCallSite walks up the stack past this package's frames and returns the first
+// caller outside it, named after the outermost builder it called
+// Test files of this package count as callers so tests see their own call sites
+func captureCallSite() *callSite {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	builder := ""
+	for {
+		frame, more := frames.Next()
+		switch {
+		case strings.HasPrefix(frame.Function, "runtime."):
+			// deferreturn and friends sit between a builder and its deferred recordCallSite
+		case strings.HasPrefix(frame.Function, packagePrefix) && !strings.HasSuffix(frame.File, "_test.go"):
+			builder = frame.Function
+		case builder != "":
+			return &callSite{builder: builderName(builder), file: filepath.Base(frame.File), line: frame.Line}
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// builderName reduces a runtime function name such as
+// "github.com/isesword/firn/polars.(*DataFrame).Filter.func1" to "Filter"
+func builderName(function string) string {
+	name := strings.TrimPrefix(function, packagePrefix)
+	if i := strings.LastIndex(name, ")."); i >= 0 {
+		name = name[i+2:]
+	}
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// withCallSite tags op with the builder call that is creating it
+func withCallSite(op Operation) Operation {
+	op.site = captureCallSite()
+	return op
+}
+
+// recordCallSite tags the operations queued since index from with the builder call site
+// Builders defer it so expression operations they flatten into the queue are covered too
+func (df *DataFrame) recordCallSite(from int) {
+	if from >= len(df.operations) {
+		return
+	}
+	site := captureCallSite()
+	for i := from; i < len(df.operations); i++ {
+		if df.operations[i].site == nil {
+			df.operations[i].site = site
+		}
+	}
+}
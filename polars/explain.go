@@ -0,0 +1,62 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+)
+
+// Explain returns the query plan Polars would run for the pending operations
+// without executing it; the DataFrame and its operation queue are left untouched.
+// With optimized, the plan is shown after predicate and projection pushdown, e.g.
+// to check that a Filter reached the Parquet scan as a SELECTION.
+func (df *DataFrame) Explain(optimized bool) (string, error) {
+	plan, err := df.asSubPlan()
+	if err != nil {
+		return "", fmt.Errorf("Explain: %w", err)
+	}
+	defer releaseHandleRefs(plan.refs())
+
+	var errorCode C.int
+	var errorMessage *C.char
+	planPtr := C.explain_plan(plan.toC(), C.bool(optimized), &errorCode, &errorMessage)
+	if planPtr == nil {
+		return "", ffiError(errorCode, errorMessage, "failed to explain plan")
+	}
+
+	planText := C.GoString(planPtr)
+	C.free_string(planPtr)
+	return planText, nil
+}
+
+// DescribeOps lists the pending Go-side operation queue, one operation per line
+// Expressions passed to Select, WithColumns and Agg are queued as expression operations
+// right before the operation that consumes them; expressions passed to Filter are built
+// into its arguments and are not listed separately
+// Example output for ReadParquet(...).Filter(...).Select("Company"):
+//
+//	0: ReadParquet
+//	1: FilterExpr
+//	2: ExprSql
+//	3: SelectExpr
+func (df *DataFrame) DescribeOps() string {
+	if len(df.operations) == 0 {
+		if df.handle.handle != 0 {
+			return "(executed, no pending operations)"
+		}
+		return "(no pending operations)"
+	}
+
+	var b strings.Builder
+	for i, op := range df.operations {
+		if op.err != nil {
+			fmt.Fprintf(&b, "%d: Error: %v\n", i, op.err) // Fails the plan at Collect()
+			continue
+		}
+		fmt.Fprintf(&b, "%d: %s\n", i, opcodeName(op.opcode))
+	}
+	return b.String()
+}
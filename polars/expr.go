@@ -146,6 +146,8 @@ func Lit(value interface{}) *ExprNode {
 // SqlExpr creates an ExprNode from a SQL expression string
 // Supports SQL expressions like "salary * 1.1", "(a + b) / c", "salary * 1.1 AS bonus_salary"
 // For supported SQL functions, see: https://docs.pola.rs/api/python/dev/reference/sql/functions/index.html
+// sql is parsed as written and has no placeholders; build expressions around untrusted
+// values with Lit instead, or filter with DataFrame.QueryParams.
 func SqlExpr(sql string) *ExprNode {
 	return &ExprNode{
 		ops: single(Operation{
@@ -390,6 +392,108 @@ func (expr *ExprNode) Var(ddof ...uint8) *ExprNode {
 	return expr.ddofAggregation(OpExprVar, "Var", ddof...)
 }
 
+// QuantileInterpolation selects how Quantile() interpolates between data points
+// Values match the mapping in rust/src/expr.rs expr_quantile
+type QuantileInterpolation uint8
+
+const (
+	QuantileNearest  QuantileInterpolation = 0
+	QuantileLower    QuantileInterpolation = 1
+	QuantileHigher   QuantileInterpolation = 2
+	QuantileMidpoint QuantileInterpolation = 3
+	QuantileLinear   QuantileInterpolation = 4
+)
+
+// Quantile applies quantile aggregation to the expression
+// quantile must be between 0 and 1
+// Usage: Col("salary").Quantile(0.95, QuantileLinear)
+func (expr *ExprNode) Quantile(quantile float64, interpolation QuantileInterpolation) *ExprNode {
+	if quantile < 0 || quantile > 1 {
+		return &ExprNode{ops: combine(expr.ops, single(errOp("Quantile() quantile must be between 0 and 1")))}
+	}
+	if interpolation > QuantileLinear {
+		return &ExprNode{ops: combine(expr.ops, single(errOpf("Quantile() unknown interpolation %d", interpolation)))}
+	}
+
+	return &ExprNode{
+		ops: combine(expr.ops, single(Operation{
+			opcode: OpExprQuantile,
+			args: func() unsafe.Pointer {
+				return unsafe.Pointer(&C.QuantileArgs{
+					quantile:      C.double(quantile),
+					interpolation: C.uchar(interpolation),
+				})
+			},
+		})),
+	}
+}
+
+// Mode returns the most frequent value(s) of the expression
+func (expr *ExprNode) Mode() *ExprNode {
+	return expr.unaryOp(OpExprMode)
+}
+
+// ApproxNUnique approximates the number of unique values (HyperLogLog)
+// Much cheaper than NUnique() on high-cardinality columns
+func (expr *ExprNode) ApproxNUnique() *ExprNode {
+	return expr.unaryOp(OpExprApproxNUnique)
+}
+
+// ArgMin returns the index of the minimum value
+func (expr *ExprNode) ArgMin() *ExprNode {
+	return expr.unaryOp(OpExprArgMin)
+}
+
+// ArgMax returns the index of the maximum value
+func (expr *ExprNode) ArgMax() *ExprNode {
+	return expr.unaryOp(OpExprArgMax)
+}
+
+// momentAggregation is a helper for skew/kurtosis operations that take MomentArgs
+func (expr *ExprNode) momentAggregation(opcode uint32, fisher, bias bool) *ExprNode {
+	return &ExprNode{
+		ops: combine(expr.ops, single(Operation{
+			opcode: opcode,
+			args: func() unsafe.Pointer {
+				return unsafe.Pointer(&C.MomentArgs{
+					fisher: C.bool(fisher),
+					bias:   C.bool(bias),
+				})
+			},
+		})),
+	}
+}
+
+// Skew computes the sample skewness of the expression
+// bias=false corrects for statistical bias
+// Usage: Col("salary").Skew(true)
+func (expr *ExprNode) Skew(bias bool) *ExprNode {
+	return expr.momentAggregation(OpExprSkew, false, bias)
+}
+
+// Kurtosis computes the kurtosis of the expression
+// fisher=true: excess kurtosis (normal = 0.0), fisher=false: Pearson's definition (normal = 3.0)
+// bias=false corrects for statistical bias
+func (expr *ExprNode) Kurtosis(fisher, bias bool) *ExprNode {
+	return expr.momentAggregation(OpExprKurtosis, fisher, bias)
+}
+
+// Product multiplies all values of the expression
+func (expr *ExprNode) Product() *ExprNode {
+	return expr.unaryOp(OpExprProduct)
+}
+
+// Implode aggregates all values of the expression into a single list
+func (expr *ExprNode) Implode() *ExprNode {
+	return expr.unaryOp(OpExprImplode)
+}
+
+// AggGroups returns the row indices belonging to each group
+// Only meaningful inside Agg()
+func (expr *ExprNode) AggGroups() *ExprNode {
+	return expr.unaryOp(OpExprAggGroups)
+}
+
 // Alias adds an alias to the expression for naming computed columns
 func (expr *ExprNode) Alias(name string) *ExprNode {
 	return expr.unaryOpWithAliasArgs(OpExprAlias, name)
@@ -759,6 +863,235 @@ func (expr *ExprNode) Lead(offset int) *ExprNode {
 	}
 }
 
+// Rolling Window Functions
+
+// rollingWindow is a helper for rolling aggregations over a fixed number of rows
+// minPeriods=0 defaults to windowSize (a result only once the window is full)
+func (expr *ExprNode) rollingWindow(opcode uint32, opName string, windowSize, minPeriods int, center bool, quantile float64, ddof uint8) *ExprNode {
+	if windowSize <= 0 {
+		return &ExprNode{ops: combine(expr.ops, single(errOpf("%s() window size must be positive", opName)))}
+	}
+	if minPeriods < 0 || minPeriods > windowSize {
+		return &ExprNode{ops: combine(expr.ops, single(errOpf("%s() min periods must be between 0 and the window size", opName)))}
+	}
+	if minPeriods == 0 {
+		minPeriods = windowSize
+	}
+
+	return &ExprNode{
+		ops: combine(expr.ops, single(Operation{
+			opcode: opcode,
+			args: func() unsafe.Pointer {
+				return unsafe.Pointer(&C.RollingArgs{
+					window_size: C.size_t(windowSize),
+					min_periods: C.size_t(minPeriods),
+					center:      C.bool(center),
+					quantile:    C.double(quantile),
+					ddof:        C.uchar(ddof),
+				})
+			},
+		})),
+	}
+}
+
+// rollingWindowBy is a helper for rolling aggregations over a duration of the index column
+// window uses Polars duration strings, e.g. "7d", "1h30m", "3i" (index count)
+func (expr *ExprNode) rollingWindowBy(opcode uint32, opName string, indexColumn, window string, quantile float64, ddof uint8) *ExprNode {
+	if indexColumn == "" {
+		return &ExprNode{ops: combine(expr.ops, single(errOpf("%s() requires an index column", opName)))}
+	}
+	if window == "" {
+		return &ExprNode{ops: combine(expr.ops, single(errOpf("%s() requires a window duration", opName)))}
+	}
+
+	return &ExprNode{
+		ops: combine(expr.ops, single(Operation{
+			opcode: opcode,
+			args: func() unsafe.Pointer {
+				return unsafe.Pointer(&C.RollingArgs{
+					min_periods: C.size_t(1),
+					by:          makeRawStr(indexColumn),
+					duration:    makeRawStr(window),
+					quantile:    C.double(quantile),
+					ddof:        C.uchar(ddof),
+				})
+			},
+		})),
+	}
+}
+
+// rollingDdof validates the optional ddof parameter of rolling std operations
+func rollingDdof(opName string, ddof []uint8) (uint8, error) {
+	if len(ddof) > 1 {
+		return 0, fmt.Errorf("%s() accepts at most one ddof parameter", opName)
+	}
+	if len(ddof) == 1 {
+		if ddof[0] != 0 && ddof[0] != 1 {
+			return 0, fmt.Errorf("ddof must be 0 (population) or 1 (sample)")
+		}
+		return ddof[0], nil
+	}
+	return 0, nil // Default to population, matching Std()
+}
+
+// RollingMean computes the mean over a sliding window of windowSize rows
+// minPeriods: minimum non-null values for a result (0 = windowSize)
+// center: label each window by its middle row instead of its last row
+// Usage: Col("price").RollingMean(7, 0, false)
+func (expr *ExprNode) RollingMean(windowSize, minPeriods int, center bool) *ExprNode {
+	return expr.rollingWindow(OpExprRollingMean, "RollingMean", windowSize, minPeriods, center, 0, 0)
+}
+
+// RollingSum computes the sum over a sliding window of windowSize rows
+func (expr *ExprNode) RollingSum(windowSize, minPeriods int, center bool) *ExprNode {
+	return expr.rollingWindow(OpExprRollingSum, "RollingSum", windowSize, minPeriods, center, 0, 0)
+}
+
+// RollingMin computes the minimum over a sliding window of windowSize rows
+func (expr *ExprNode) RollingMin(windowSize, minPeriods int, center bool) *ExprNode {
+	return expr.rollingWindow(OpExprRollingMin, "RollingMin", windowSize, minPeriods, center, 0, 0)
+}
+
+// RollingMax computes the maximum over a sliding window of windowSize rows
+func (expr *ExprNode) RollingMax(windowSize, minPeriods int, center bool) *ExprNode {
+	return expr.rollingWindow(OpExprRollingMax, "RollingMax", windowSize, minPeriods, center, 0, 0)
+}
+
+// RollingStd computes the standard deviation over a sliding window of windowSize rows
+// ddof=0: population std (default), ddof=1: sample std (unbiased)
+func (expr *ExprNode) RollingStd(windowSize, minPeriods int, center bool, ddof ...uint8) *ExprNode {
+	ddofValue, err := rollingDdof("RollingStd", ddof)
+	if err != nil {
+		return &ExprNode{ops: combine(expr.ops, single(errOp(err.Error())))}
+	}
+	return expr.rollingWindow(OpExprRollingStd, "RollingStd", windowSize, minPeriods, center, 0, ddofValue)
+}
+
+// RollingMedian computes the median over a sliding window of windowSize rows
+func (expr *ExprNode) RollingMedian(windowSize, minPeriods int, center bool) *ExprNode {
+	return expr.rollingWindow(OpExprRollingMedian, "RollingMedian", windowSize, minPeriods, center, 0, 0)
+}
+
+// RollingQuantile computes the quantile (linear interpolation) over a sliding window of windowSize rows
+// quantile must be between 0 and 1
+func (expr *ExprNode) RollingQuantile(quantile float64, windowSize, minPeriods int, center bool) *ExprNode {
+	if quantile < 0 || quantile > 1 {
+		return &ExprNode{ops: combine(expr.ops, single(errOp("RollingQuantile() quantile must be between 0 and 1")))}
+	}
+	return expr.rollingWindow(OpExprRollingQuantile, "RollingQuantile", windowSize, minPeriods, center, quantile, 0)
+}
+
+// RollingMeanBy computes the mean over a time window of the index column
+// window is a Polars duration string such as "7d" or "2h"; the window is closed on the right
+// Usage: Col("price").RollingMeanBy("date", "7d")
+func (expr *ExprNode) RollingMeanBy(indexColumn, window string) *ExprNode {
+	return expr.rollingWindowBy(OpExprRollingMean, "RollingMeanBy", indexColumn, window, 0, 0)
+}
+
+// RollingSumBy computes the sum over a time window of the index column
+func (expr *ExprNode) RollingSumBy(indexColumn, window string) *ExprNode {
+	return expr.rollingWindowBy(OpExprRollingSum, "RollingSumBy", indexColumn, window, 0, 0)
+}
+
+// RollingMinBy computes the minimum over a time window of the index column
+func (expr *ExprNode) RollingMinBy(indexColumn, window string) *ExprNode {
+	return expr.rollingWindowBy(OpExprRollingMin, "RollingMinBy", indexColumn, window, 0, 0)
+}
+
+// RollingMaxBy computes the maximum over a time window of the index column
+func (expr *ExprNode) RollingMaxBy(indexColumn, window string) *ExprNode {
+	return expr.rollingWindowBy(OpExprRollingMax, "RollingMaxBy", indexColumn, window, 0, 0)
+}
+
+// RollingStdBy computes the standard deviation over a time window of the index column
+// ddof=0: population std (default), ddof=1: sample std (unbiased)
+func (expr *ExprNode) RollingStdBy(indexColumn, window string, ddof ...uint8) *ExprNode {
+	ddofValue, err := rollingDdof("RollingStdBy", ddof)
+	if err != nil {
+		return &ExprNode{ops: combine(expr.ops, single(errOp(err.Error())))}
+	}
+	return expr.rollingWindowBy(OpExprRollingStd, "RollingStdBy", indexColumn, window, 0, ddofValue)
+}
+
+// RollingMedianBy computes the median over a time window of the index column
+func (expr *ExprNode) RollingMedianBy(indexColumn, window string) *ExprNode {
+	return expr.rollingWindowBy(OpExprRollingMedian, "RollingMedianBy", indexColumn, window, 0, 0)
+}
+
+// RollingQuantileBy computes the quantile (linear interpolation) over a time window of the index column
+func (expr *ExprNode) RollingQuantileBy(quantile float64, indexColumn, window string) *ExprNode {
+	if quantile < 0 || quantile > 1 {
+		return &ExprNode{ops: combine(expr.ops, single(errOp("RollingQuantileBy() quantile must be between 0 and 1")))}
+	}
+	return expr.rollingWindowBy(OpExprRollingQuantile, "RollingQuantileBy", indexColumn, window, quantile, 0)
+}
+
+// Cumulative Functions
+
+// cumulativeOp is a helper for cumulative operations that take CumArgs
+func (expr *ExprNode) cumulativeOp(opcode uint32, reverse []bool) *ExprNode {
+	rev := len(reverse) > 0 && reverse[0]
+	return &ExprNode{
+		ops: combine(expr.ops, single(Operation{
+			opcode: opcode,
+			args: func() unsafe.Pointer {
+				return unsafe.Pointer(&C.CumArgs{reverse: C.bool(rev)})
+			},
+		})),
+	}
+}
+
+// CumSum returns the cumulative sum; pass true to accumulate from the last row
+// Usage: Col("sales").CumSum() or Col("sales").CumSum(true)
+func (expr *ExprNode) CumSum(reverse ...bool) *ExprNode {
+	return expr.cumulativeOp(OpExprCumSum, reverse)
+}
+
+// CumMin returns the cumulative minimum
+func (expr *ExprNode) CumMin(reverse ...bool) *ExprNode {
+	return expr.cumulativeOp(OpExprCumMin, reverse)
+}
+
+// CumMax returns the cumulative maximum
+func (expr *ExprNode) CumMax(reverse ...bool) *ExprNode {
+	return expr.cumulativeOp(OpExprCumMax, reverse)
+}
+
+// CumProd returns the cumulative product
+func (expr *ExprNode) CumProd(reverse ...bool) *ExprNode {
+	return expr.cumulativeOp(OpExprCumProd, reverse)
+}
+
+// CumCount returns the cumulative count of non-null values
+func (expr *ExprNode) CumCount(reverse ...bool) *ExprNode {
+	return expr.cumulativeOp(OpExprCumCount, reverse)
+}
+
+// diffOp is a helper for Diff/PctChange which take a row offset
+func (expr *ExprNode) diffOp(opcode uint32, n int64) *ExprNode {
+	return &ExprNode{
+		ops: combine(expr.ops, single(Operation{
+			opcode: opcode,
+			args: func() unsafe.Pointer {
+				return unsafe.Pointer(&C.DiffArgs{n: C.int64_t(n)})
+			},
+		})),
+	}
+}
+
+// Diff returns the difference between each value and the value n rows earlier
+// The first n rows are null; negative n compares with following rows
+// Usage: Col("price").Diff(1)
+func (expr *ExprNode) Diff(n int64) *ExprNode {
+	return expr.diffOp(OpExprDiff, n)
+}
+
+// PctChange returns the fractional change between each value and the value n rows earlier
+// Usage: Col("price").PctChange(1)
+func (expr *ExprNode) PctChange(n int64) *ExprNode {
+	return expr.diffOp(OpExprPctChange, n)
+}
+
 // Conditional Expressions (When/Then/Otherwise)
 
 // When starts a conditional expression with a condition
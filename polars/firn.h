@@ -11,6 +11,12 @@ typedef struct {
     size_t len;
 } RawStr;
 
+// Generic operation structure with opcode and args
+typedef struct {
+    uint32_t opcode;       // OpCode for the operation
+    uintptr_t args;        // Pointer to operation-specific args as uintptr_t
+} Operation;
+
 // Operation-specific argument structs
 typedef struct {
     RawStr* columns;
@@ -18,10 +24,43 @@ typedef struct {
 } SelectArgs;
 
 
+typedef struct {
+    RawStr name;    // Column name
+    uint32_t dtype; // Bit-packed DataType
+} SchemaOverride;
+
+// Object store settings for s3:// paths (empty strings fall back to the environment)
+typedef struct {
+    RawStr endpoint;          // Custom endpoint, e.g. a local MinIO (empty = AWS)
+    RawStr region;            // Region
+    RawStr access_key_id;     // Static credentials (empty = default credential chain)
+    RawStr secret_access_key;
+    RawStr session_token;     // Optional session token for temporary credentials
+    bool allow_http;          // Permit plain-HTTP endpoints
+} CloudArgs;
+
 typedef struct {
     RawStr path;
-    bool has_header;  // Whether CSV has header row
-    bool with_glob;   // Whether to enable glob pattern expansion
+    bool has_header;                   // Whether CSV has header row
+    bool with_glob;                    // Whether to enable glob pattern expansion
+    uint8_t separator;                 // Field separator (0 = ',')
+    uint8_t quote_char;                // Quote character (0 = '"')
+    RawStr comment_prefix;             // Skip lines starting with this prefix (empty = none)
+    size_t skip_rows;                  // Rows to skip before the header
+    size_t n_rows;                     // Row limit (0 = all rows)
+    SchemaOverride* schema_overrides;  // Column dtype overrides (null = none)
+    size_t schema_override_count;      // Number of overrides
+    RawStr* null_values;               // Strings read as null (null = none)
+    size_t null_value_count;           // Number of null value strings
+    int64_t infer_schema_length;       // Rows used for inference (0 = default, <0 = all)
+    bool try_parse_dates;              // Parse date/datetime-looking strings
+    bool ignore_errors;                // Turn unparsable values into nulls
+    uint8_t encoding;                  // 0 = utf8, 1 = utf8-lossy
+    bool low_memory;                   // Reduce memory usage at the cost of speed
+    RawStr row_index_name;             // Row index column name (empty = none)
+    uint32_t row_index_offset;         // First value of the row index
+    CloudArgs* cloud;                  // Object store settings (null = none)
+    RawStr include_file_paths;         // Source file path column name (empty = none)
 } ReadCsvArgs;
 
 typedef struct {
@@ -30,13 +69,54 @@ typedef struct {
     size_t column_count;   // Number of columns to select (0 if all columns)
     size_t n_rows;         // Optional row limit (0 = all rows)
     bool parallel;         // Enable parallel reading
-    bool with_glob;        // Whether to expand glob patterns
+    bool with_glob;        // Ignored; globs are always expanded
+    bool hive_partitioning;            // Parse key=value directories into columns (false = Polars default)
+    SchemaOverride* hive_schema;       // Partition column dtypes (null = inferred)
+    size_t hive_schema_count;          // Number of partition column dtypes
+    bool try_parse_hive_dates;         // Parse date-looking partition values as dates
+    CloudArgs* cloud;                  // Object store settings (null = none)
+    RawStr row_index_name;             // Row index column name (empty = none)
+    uint32_t row_index_offset;         // First value of the row index
+    RawStr include_file_paths;         // Source file path column name (empty = none)
+    bool low_memory;                   // Reduce memory usage at the cost of speed
+    bool use_statistics;               // Skip row groups using min/max statistics
+    bool rechunk;                      // Rechunk the result into contiguous memory
+    Operation* predicate_ops;          // Filter expression pushed into the scan (null = none)
+    size_t predicate_count;            // Number of predicate expression operations
 } ReadParquetArgs;
 
 typedef struct {
-    uintptr_t* handles; // Array of DataFrame handles
-    size_t count;       // Number of handles
-} ConcatArgs;
+    RawStr path;                 // File path or glob pattern
+    int64_t infer_schema_length; // Rows used for inference (0 = default, <0 = all)
+    size_t batch_size;           // Rows per parsing batch (0 = default)
+    size_t n_rows;               // Row limit (0 = all rows)
+} ReadNdjsonArgs;
+
+typedef struct {
+    RawStr path;                 // File path
+    int64_t infer_schema_length; // Rows used for inference (0 = default, <0 = all)
+} ReadJsonArgs;
+
+typedef struct {
+    RawStr path;     // File path (glob patterns only for scans)
+    size_t n_rows;   // Row limit (0 = all rows)
+    bool memory_map; // Memory-map the file (eager reads only)
+} ReadIpcArgs;
+
+typedef struct {
+    const uint8_t* data; // Buffer contents
+    size_t len;          // Buffer length in bytes
+} BufferArgs;
+
+typedef struct {
+    BufferArgs buffer;   // CSV text
+    ReadCsvArgs options; // Parsing options (path and with_glob are ignored)
+} ReadCsvBufferArgs;
+
+typedef struct {
+    double* percentiles;     // Percentiles in [0, 1] (null = 25%, 50%, 75%)
+    size_t percentile_count; // Number of percentiles
+} DescribeArgs;
 
 typedef struct {
     RawStr name; // Column alias name
@@ -46,6 +126,16 @@ typedef struct {
     unsigned char ddof; // Delta degrees of freedom (0=population, 1=sample)
 } AggregationArgs;
 
+typedef struct {
+    double quantile;             // Quantile in [0, 1]
+    unsigned char interpolation; // 0=nearest, 1=lower, 2=higher, 3=midpoint, 4=linear
+} QuantileArgs;
+
+typedef struct {
+    bool fisher; // Kurtosis only: subtract 3 so a normal distribution has 0.0
+    bool bias;   // If false, correct for statistical bias
+} MomentArgs;
+
 typedef struct {
     bool include_nulls; // Whether to include null values in count
 } CountArgs;
@@ -119,8 +209,19 @@ typedef struct {
     size_t n;            // Number of rows to limit to
 } LimitArgs;
 
+// Centralized literal abstraction - handles all value types
+typedef struct {
+    int value_type;       // 0=int, 1=float, 2=string, 3=bool, 4=null
+    long long int_value;
+    double float_value;
+    RawStr string_value;
+    _Bool bool_value;
+} Literal;
+
 typedef struct {
     RawStr sql;
+    const Literal* params;   // Values for the $n / ? placeholders in sql
+    size_t param_count;      // 0 = run sql as written
 } QueryArgs;
 
 typedef struct {
@@ -136,17 +237,6 @@ typedef enum {
     JoinTypeCross = 4
 } JoinType;
 
-// Arguments for join operations
-typedef struct {
-    uintptr_t other_handle;     // Handle to the right DataFrame
-    RawStr* left_on;            // Left join columns 
-    RawStr* right_on;           // Right join columns
-    uintptr_t column_count;     // Number of join columns
-    JoinType how;               // Join type (inner, left, etc.)
-    RawStr suffix;              // Optional suffix for duplicate columns
-    bool coalesce;              // Whether to coalesce join columns (default false)
-} JoinArgs;
-
 // Window function arguments
 typedef struct {
     RawStr* partition_columns;
@@ -166,14 +256,34 @@ typedef struct {
     bool wrap_numerical;     // If true, wrap overflowing numeric values instead of marking invalid
 } CastArgs;
 
-// Centralized literal abstraction - handles all value types
+// MapBatches arguments: a Go function applied to whole columns
 typedef struct {
-    int value_type;       // 0=int, 1=float, 2=string, 3=bool
-    long long int_value;
-    double float_value;
-    RawStr string_value;
-    _Bool bool_value;
-} Literal;
+    uintptr_t udf;           // Go cgo.Handle of the function, passed back to the UDF callback
+    uint32_t return_type;    // Output data type (bit-packed encoding)
+} MapBatchesArgs;
+
+// Rolling window arguments
+// An empty `by` selects a fixed window of window_size rows; otherwise the
+// window spans `duration` over the index column named by `by`
+typedef struct {
+    size_t window_size;      // Window length in rows (fixed windows only)
+    size_t min_periods;      // Minimum number of non-null values required for a result
+    bool center;             // Whether to center the window labels (fixed windows only)
+    RawStr by;               // Index column for duration-based windows (empty = fixed window)
+    RawStr duration;         // Window duration such as "7d" (duration-based windows only)
+    double quantile;         // Quantile in [0, 1] (rolling quantile only)
+    unsigned char ddof;      // Delta degrees of freedom (rolling std only)
+} RollingArgs;
+
+// Cumulative operation arguments
+typedef struct {
+    bool reverse;            // Accumulate from the last row towards the first
+} CumArgs;
+
+// Diff / pct_change arguments
+typedef struct {
+    int64_t n;               // Number of rows to shift when computing the difference
+} DiffArgs;
 
 // Arguments for expression operations
 typedef struct {
@@ -184,12 +294,6 @@ typedef struct {
     Literal literal;
 } LiteralArgs;
 
-// Generic operation structure with opcode and args
-typedef struct {
-    uint32_t opcode;       // OpCode for the operation
-    uintptr_t args;        // Pointer to operation-specific args as uintptr_t
-} Operation;
-
 // Filter with expression arguments
 typedef struct {
     Operation* expr_ops;  // Note: using Operation instead of ExprOp
@@ -202,6 +306,49 @@ typedef struct {
     uint32_t context_type; // ContextType as u32 for C compatibility
 } PolarsHandle;
 
+// Plan embedded in another operation's arguments (e.g. the right side of a join)
+// handle is the starting point (0 if the first operation creates the data) and
+// operations are executed lazily within the same execute_operations call
+typedef struct {
+    PolarsHandle handle;
+    const Operation* operations;
+    size_t count;
+} SubPlan;
+
+typedef struct {
+    SubPlan* inputs; // Array of input plans
+    size_t count;    // Number of inputs
+} ConcatArgs;
+
+// A table registered with a SQL context
+typedef struct {
+    RawStr name;
+    SubPlan plan;    // Executed lazily when the query is planned
+} SqlTable;
+
+// Arguments for SQL over several named tables
+typedef struct {
+    SqlTable* tables; // Array of registered tables (may be NULL when count is 0)
+    size_t count;     // Number of tables
+    RawStr sql;
+} SqlContextArgs;
+
+// Arguments for starting a query from a serialized plan
+typedef struct {
+    RawStr json;      // Plan JSON from serialize_plan
+} PlanArgs;
+
+// Arguments for join operations
+typedef struct {
+    SubPlan other;              // Right side, executed lazily in the same call
+    RawStr* left_on;            // Left join columns 
+    RawStr* right_on;           // Right join columns
+    uintptr_t column_count;     // Number of join columns
+    JoinType how;               // Join type (inner, left, etc.)
+    RawStr suffix;              // Optional suffix for duplicate columns
+    bool coalesce;              // Whether to coalesce join columns (default false)
+} JoinArgs;
+
 typedef struct {
     PolarsHandle polars_handle; // Handle with context type
     int error_code;
@@ -231,13 +378,75 @@ typedef struct {
 
 // Core FFI functions - these are the only functions called from Go
 FfiResult execute_operations(PolarsHandle handle, const Operation* operations, size_t count);
+// DataFrame handles are reference counted: each retain needs a matching release
+int retain_dataframe(uintptr_t handle);
 int release_dataframe(uintptr_t handle);
 void free_string(char* error_message);
 
-// DataFrame introspection
-size_t dataframe_height(uintptr_t handle);
-char* dataframe_to_csv(uintptr_t handle);
-char* dataframe_to_string(uintptr_t handle);
+// DataFrame introspection (failures, including caught panics, set error_code and error_message)
+size_t dataframe_height(uintptr_t handle, int* error_code, char** error_message);
+size_t dataframe_estimated_size(uintptr_t handle, int* error_code, char** error_message);
+char* dataframe_to_csv(uintptr_t handle, int* error_code, char** error_message);
+char* dataframe_to_string(uintptr_t handle, int* error_code, char** error_message);
+char* dataframe_to_json(uintptr_t handle, int* error_code, char** error_message);
+char* dataframe_schema(uintptr_t handle, int* error_code, char** error_message);
+
+// Arrow IPC writing (compression: 0 = none, 1 = LZ4, 2 = ZSTD)
+// Buffer encoders stop with ERROR_CANCELLED once token (0 = none) is cancelled
+int dataframe_write_ipc(uintptr_t handle, RawStr path, uint8_t compression, char** error_message);
+uint8_t* dataframe_to_ipc_stream(uintptr_t handle, uint8_t compression, uintptr_t token, size_t* out_len,
+                                 int* error_code, char** error_message);
+void free_buffer(uint8_t* data, size_t len);
+
+// CSV / Parquet serialization into a buffer (format: 0 = CSV, 1 = Parquet)
+uint8_t* dataframe_to_buffer(uintptr_t handle, uint8_t format, uintptr_t token, size_t* out_len,
+                             int* error_code, char** error_message);
+
+// Hive-partitioned Parquet dataset writing (dir/key=value/00000000.parquet)
+int dataframe_write_parquet_partitioned(uintptr_t handle, RawStr dir, const RawStr* partition_by,
+                                        size_t partition_count, char** error_message);
+
+// Cancellable execution: cancel_token_cancel may be called from any thread while
+// execute_operations_cancellable runs; the token is freed once the call has returned
+uintptr_t cancel_token_new(void);
+void cancel_token_cancel(uintptr_t token);
+void cancel_token_free(uintptr_t token);
+FfiResult execute_operations_cancellable(PolarsHandle handle, const Operation* operations, size_t count,
+                                         uintptr_t token);
+
+// Query plan of a sub-plan as text (free with free_string); null on error
+char* explain_plan(SubPlan plan, bool optimized, int* error_code, char** error_message);
+
+// Logical plan of a sub-plan as Polars JSON (free with free_string); null on error.
+// The JSON is only readable by the Polars release plan_polars_version returns
+char* serialize_plan(SubPlan plan, int* error_code, char** error_message);
+const char* plan_polars_version(void);
+
+// Parquet footer metadata as JSON (free with free_string); null on error
+char* read_parquet_metadata(RawStr path, int* error_code, char** error_message);
+
+// Start the Polars thread pool if needed and return its size, or 0 on a panic; the pool
+// is sized from POLARS_MAX_THREADS when it starts and cannot be resized afterwards
+size_t thread_pool_init(int* error_code, char** error_message);
+
+// Go UDFs: Rust calls the registered callback with a view of each batch, and the
+// callback reports its result with udf_output_set (copied) or udf_output_error
+typedef struct {
+    RawStr name;
+    uint32_t dtype;          // Bit-packed DataType
+    size_t len;              // Number of rows
+    const void* values;      // len fixed-width values (Boolean: one byte each), or len+1 int64 String offsets
+    const uint8_t* data;     // String bytes, indexed by the offsets (String only)
+    const uint8_t* validity; // Arrow validity bitmap, bit set = row is valid; NULL when no row is null
+    size_t validity_offset;  // Bit index of the first row in validity
+} SeriesView;
+
+typedef struct UdfOutput UdfOutput;
+typedef int (*UdfCallback)(uintptr_t udf, SeriesView* input, UdfOutput* output);
+
+void register_udf_callback(UdfCallback callback);
+int udf_output_set(UdfOutput* output, SeriesView* view);
+void udf_output_error(UdfOutput* output, RawStr message);
 
 // Testing and benchmarking helpers
 FfiResult dispatch_add_null_row(uintptr_t handle, uintptr_t args);
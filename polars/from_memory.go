@@ -114,7 +114,7 @@ func FromColumns(columns map[string][]any) *DataFrame {
 
 	return &DataFrame{
 		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)},
-		operations: []Operation{op},
+		operations: []Operation{withCallSite(op)},
 	}
 }
 
@@ -0,0 +1,136 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"cmp"
+	"errors"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// LiveHandle describes a reference to a Rust DataFrame handle that has not been released yet
+// Rust reference-counts handles, so a DataFrame embedded in a pending Join or Concat
+// shows up once for itself and once for each plan embedding it
+type LiveHandle struct {
+	ID    uint64 // Allocation order, unique for the life of the process
+	Stack string // Goroutine stack at allocation; empty unless SetHandleDebug(true)
+}
+
+// handleRegistry tracks every handle reference taken by Go until it is released
+// A handle stays valid while any of its references is registered, since a reference
+// leaves the registry before Rust is told to drop it.
+var handleRegistry = struct {
+	sync.Mutex
+	live map[uint64]registeredHandle
+}{live: make(map[uint64]registeredHandle)}
+
+// registeredHandle is a handleRegistry entry
+type registeredHandle struct {
+	LiveHandle
+	handle C.uintptr_t
+}
+
+var (
+	nextHandleID atomic.Uint64
+	handleDebug  atomic.Bool
+)
+
+func init() {
+	handleDebug.Store(os.Getenv("FIRN_DEBUG_HANDLES") == "1")
+}
+
+// SetHandleDebug turns recording of allocation stacks for LiveHandles on or off
+// It can also be enabled with FIRN_DEBUG_HANDLES=1; stacks cost an allocation per Collect
+func SetHandleDebug(enabled bool) {
+	handleDebug.Store(enabled)
+}
+
+// LiveHandles lists the Rust DataFrame handle references still held, oldest first
+// A reference is released by DataFrame.Release, by executing the plan that embedded it,
+// or, once nothing using it is reachable, by the garbage collector. Tests can compare
+// IDs before and after a case to assert it released everything it collected.
+func LiveHandles() []LiveHandle {
+	handleRegistry.Lock()
+	handles := make([]LiveHandle, 0, len(handleRegistry.live))
+	for _, h := range handleRegistry.live {
+		handles = append(handles, h.LiveHandle)
+	}
+	handleRegistry.Unlock()
+
+	slices.SortFunc(handles, func(a, b LiveHandle) int { return cmp.Compare(a.ID, b.ID) })
+	return handles
+}
+
+// handleRef owns one reference to a Rust DataFrame handle. Every DataFrame and sub-plan
+// using a handle holds its own ref, so releasing one never frees the handle under another.
+type handleRef struct {
+	handle  C.uintptr_t
+	id      uint64
+	cleanup runtime.Cleanup
+}
+
+// newHandleRef registers a handle produced by execute and attaches its cleanup
+func newHandleRef(handle C.uintptr_t) *handleRef {
+	live := LiveHandle{ID: nextHandleID.Add(1)}
+	if handleDebug.Load() {
+		live.Stack = string(debug.Stack())
+	}
+	handleRegistry.Lock()
+	handleRegistry.live[live.ID] = registeredHandle{LiveHandle: live, handle: handle}
+	handleRegistry.Unlock()
+
+	ref := &handleRef{handle: handle, id: live.ID}
+	// The cleanup must not reference ref itself, or ref would never become unreachable
+	ref.cleanup = runtime.AddCleanup(ref, func(h trackedHandle) { _ = h.release() },
+		trackedHandle{handle: handle, id: live.ID})
+	return ref
+}
+
+// retainHandleRef takes another reference to a handle some other ref already holds
+func retainHandleRef(handle C.uintptr_t) *handleRef {
+	C.retain_dataframe(handle)
+	return newHandleRef(handle)
+}
+
+// releaseHandleRefs releases refs, ignoring errors like the garbage collector would
+func releaseHandleRefs(refs []*handleRef) {
+	for _, ref := range refs {
+		_ = ref.release()
+	}
+}
+
+// release drops the reference now; later calls and the cleanup become no-ops
+func (ref *handleRef) release() error {
+	ref.cleanup.Stop()
+	return trackedHandle{handle: ref.handle, id: ref.id}.release()
+}
+
+// trackedHandle is the registry key and handle a cleanup needs to release
+type trackedHandle struct {
+	handle C.uintptr_t
+	id     uint64
+}
+
+// release drops the reference if it is still registered; Rust frees the handle with its last one
+// Keyed by ID rather than address, since Rust may reuse a freed address for a new handle
+func (h trackedHandle) release() error {
+	handleRegistry.Lock()
+	_, live := handleRegistry.live[h.id]
+	delete(handleRegistry.live, h.id)
+	handleRegistry.Unlock()
+
+	if !live {
+		return nil
+	}
+	if C.release_dataframe(h.handle) != 0 {
+		return errors.New("failed to release dataframe")
+	}
+	return nil
+}
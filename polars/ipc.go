@@ -0,0 +1,149 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"unsafe"
+)
+
+// IPCCompression selects the compression codec for Arrow IPC output
+type IPCCompression uint8
+
+const (
+	IPCCompressionNone IPCCompression = 0
+	IPCCompressionLZ4  IPCCompression = 1
+	IPCCompressionZSTD IPCCompression = 2
+)
+
+// IPCOptions configures eager Arrow IPC reading options
+type IPCOptions struct {
+	NRows     int  // Optional row limit (0 = all rows)
+	MemoryMap bool // Memory-map the file instead of reading it into memory
+}
+
+// ReadIPC reads an Arrow IPC (Feather v2) file with default options
+// - memory_map: true (buffers are mapped, not copied, where possible)
+func ReadIPC(path string) *DataFrame {
+	return ReadIPCWithOptions(path, IPCOptions{MemoryMap: true})
+}
+
+// ReadIPCWithOptions eagerly reads an Arrow IPC (Feather v2) file
+func ReadIPCWithOptions(path string, options IPCOptions) *DataFrame {
+	if options.NRows < 0 {
+		return NewDataFrame().appendErrOpf("ReadIPC: NRows cannot be negative, got %d", options.NRows)
+	}
+
+	op := Operation{
+		opcode: OpReadIpc,
+		args: func() unsafe.Pointer {
+			return unsafe.Pointer(&C.ReadIpcArgs{
+				path:       makeRawStr(path), // path captured by closure
+				n_rows:     C.size_t(options.NRows),
+				memory_map: C.bool(options.MemoryMap),
+			})
+		},
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// ScanIPC lazily scans Arrow IPC files so filters and projections are pushed down
+// Glob patterns like "parts/*.arrow" are expanded
+func ScanIPC(path string) *DataFrame {
+	op := Operation{
+		opcode: OpScanIpc,
+		args: func() unsafe.Pointer {
+			return unsafe.Pointer(&C.ReadIpcArgs{
+				path: makeRawStr(path), // path captured by closure
+			})
+		},
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// ReadIPCStream reads an Arrow IPC stream (not file) from r, e.g. a socket or pipe
+// The stream is read to EOF immediately; decoding happens when the DataFrame is executed
+func ReadIPCStream(r io.Reader) *DataFrame {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return NewDataFrame().appendErrOpf("ReadIPCStream: %v", err)
+	}
+	if len(data) == 0 {
+		return NewDataFrame().appendErrOp("ReadIPCStream: stream is empty")
+	}
+
+	op := Operation{
+		opcode: OpReadIpcStream,
+		args: func() unsafe.Pointer {
+			return unsafe.Pointer(&C.BufferArgs{
+				data: (*C.uint8_t)(unsafe.Pointer(&data[0])), // data captured by closure
+				len:  C.size_t(len(data)),
+			})
+		},
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// WriteIPC writes an executed DataFrame to an Arrow IPC (Feather v2) file
+func (df *DataFrame) WriteIPC(path string, compression IPCCompression) error {
+	if df.handle.handle == 0 {
+		return errors.New("dataframe not executed - call Collect() first")
+	}
+
+	var errorMessage *C.char
+	code := C.dataframe_write_ipc(df.handle.handle, makeRawStr(path), C.uint8_t(compression), &errorMessage)
+	runtime.KeepAlive(df)
+	if code != 0 {
+		return ffiError(code, errorMessage, "failed to write IPC file")
+	}
+	return nil
+}
+
+// WriteIPCStream writes an executed DataFrame to w as an Arrow IPC stream
+func (df *DataFrame) WriteIPCStream(w io.Writer, compression IPCCompression) error {
+	return df.writeIPCStream(context.Background(), w, compression, "WriteIPCStream")
+}
+
+// WriteIPCStreamContext is WriteIPCStream that stops with ctx.Err() once ctx is done
+// Encoding stops at its next write; writing to w stops between chunks, not mid-Write
+func (df *DataFrame) WriteIPCStreamContext(ctx context.Context, w io.Writer, compression IPCCompression) error {
+	return df.writeIPCStream(ctx, w, compression, "WriteIPCStreamContext")
+}
+
+func (df *DataFrame) writeIPCStream(ctx context.Context, w io.Writer, compression IPCCompression, name string) error {
+	if df.handle.handle == 0 {
+		return errors.New("dataframe not executed - call Collect() first")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	buffer, length, err := encodeCancellable(ctx, "failed to encode IPC stream",
+		func(token C.uintptr_t, length *C.size_t, errorCode *C.int, errorMessage **C.char) *C.uint8_t {
+			return C.dataframe_to_ipc_stream(df.handle.handle, C.uint8_t(compression), token, length, errorCode, errorMessage)
+		})
+	runtime.KeepAlive(df)
+	if err != nil {
+		return err
+	}
+	defer C.free_buffer(buffer, length)
+
+	return writeContext(ctx, w, unsafe.Slice((*byte)(unsafe.Pointer(buffer)), int(length)), name)
+}
@@ -83,7 +83,13 @@ func (spec JoinSpec) WithCoalesce(coalesce bool) JoinSpec {
 }
 
 // Join performs a join operation with another DataFrame
+// other may be executed or lazy; a lazy other is embedded as a sub-plan and
+// executed in the same Collect(), so filters and projections are pushed down
+// into both inputs. The result is lazy until collected. other is snapshotted with its own
+// handle reference: collecting or releasing other afterwards does not change the join.
 func (df *DataFrame) Join(other *DataFrame, spec JoinSpec) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	// Validate inputs
 	if other == nil {
 		return df.appendErrOp("Join: other DataFrame cannot be nil")
@@ -98,9 +104,10 @@ func (df *DataFrame) Join(other *DataFrame, spec JoinSpec) *DataFrame {
 			len(spec.leftOn), len(spec.rightOn))
 	}
 
-	// We need the other DataFrame to be executed to get its handle
-	if other.handle.handle == 0 {
-		return df.appendErrOp("Join: other DataFrame must be executed first (call Collect())")
+	// Embed the other DataFrame's plan so both sides are optimized together
+	otherPlan, err := other.asSubPlan()
+	if err != nil {
+		return df.appendErrOpf("Join: other DataFrame: %v", err)
 	}
 
 	op := Operation{
@@ -119,7 +126,7 @@ func (df *DataFrame) Join(other *DataFrame, spec JoinSpec) *DataFrame {
 			}
 
 			return unsafe.Pointer(&C.JoinArgs{
-				other_handle:  C.uintptr_t(other.handle.handle),
+				other:        otherPlan.toC(),
 				left_on:      (*C.RawStr)(unsafe.Pointer(&leftRawStrs[0])),
 				right_on:     (*C.RawStr)(unsafe.Pointer(&rightRawStrs[0])),
 				column_count: C.uintptr_t(len(spec.leftOn)),
@@ -128,6 +135,8 @@ func (df *DataFrame) Join(other *DataFrame, spec JoinSpec) *DataFrame {
 				coalesce:     C.bool(spec.coalesce),
 			})
 		},
+		refs:   otherPlan.refs(),
+		secret: otherPlan.secret(),
 	}
 
 	df.operations = append(df.operations, op)
@@ -158,14 +167,17 @@ func (df *DataFrame) OuterJoin(other *DataFrame, columns ...string) *DataFrame {
 
 // CrossJoin performs a cross join (Cartesian product)
 func (df *DataFrame) CrossJoin(other *DataFrame) *DataFrame {
+	defer df.recordCallSite(len(df.operations))
+
 	// Validate inputs
 	if other == nil {
 		return df.appendErrOp("CrossJoin: other DataFrame cannot be nil")
 	}
 
-	// We need the other DataFrame to be executed to get its handle
-	if other.handle.handle == 0 {
-		return df.appendErrOp("CrossJoin: other DataFrame must be executed first (call Collect())")
+	// Embed the other DataFrame's plan so both sides are optimized together
+	otherPlan, err := other.asSubPlan()
+	if err != nil {
+		return df.appendErrOpf("CrossJoin: other DataFrame: %v", err)
 	}
 
 	op := Operation{
@@ -173,7 +185,7 @@ func (df *DataFrame) CrossJoin(other *DataFrame) *DataFrame {
 		args: func() unsafe.Pointer {
 			// Cross join doesn't use join columns, so pass empty arrays
 			return unsafe.Pointer(&C.JoinArgs{
-				other_handle:  C.uintptr_t(other.handle.handle),
+				other:        otherPlan.toC(),
 				left_on:      nil, // No join columns for cross join
 				right_on:     nil, // No join columns for cross join
 				column_count: C.uintptr_t(0), // No columns
@@ -182,6 +194,8 @@ func (df *DataFrame) CrossJoin(other *DataFrame) *DataFrame {
 				coalesce:     C.bool(false),
 			})
 		},
+		refs:   otherPlan.refs(),
+		secret: otherPlan.secret(),
 	}
 
 	df.operations = append(df.operations, op)
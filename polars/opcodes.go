@@ -4,6 +4,7 @@ package polars
 #include "firn.h"
 */
 import "C"
+import "fmt"
 
 // OpCode constants matching Rust OpCode enum
 // IMPORTANT: When adding/changing opcodes in rust/src/opcodes.rs,
@@ -28,6 +29,29 @@ const (
 	OpQuery       = 16
 	OpJoin        = 17
 	OpFromMemory  = 18
+	OpDescribe    = 19
+	OpNullCount   = 20
+	OpReadNdjson  = 21
+	OpReadJson    = 22
+
+	// Arrow IPC operations
+	OpReadIpc       = 23
+	OpScanIpc       = 24
+	OpReadIpcStream = 25
+
+	// In-memory buffer operations
+	OpReadCsvBuffer     = 26
+	OpReadParquetBuffer = 27
+	OpReadNdjsonBuffer  = 28
+
+	// Testing support
+	OpRaisePanic = 29
+
+	// SQL over several named tables
+	OpSqlContext = 30
+
+	// Lazy query from a serialized plan
+	OpDeserializePlan = 31
 
 	// Expression operations (stack-based)
 	OpExprColumn         = 100
@@ -98,9 +122,172 @@ const (
 	// Cast operations
 	OpExprCast = 200 // Cast expression to different data type
 
+	// Rolling window operations (fixed row count, or duration when RollingArgs.by is set)
+	OpExprRollingMean     = 210
+	OpExprRollingSum      = 211
+	OpExprRollingMin      = 212
+	OpExprRollingMax      = 213
+	OpExprRollingStd      = 214
+	OpExprRollingMedian   = 215
+	OpExprRollingQuantile = 216
+
+	// Cumulative and difference operations
+	OpExprCumSum    = 220
+	OpExprCumMin    = 221
+	OpExprCumMax    = 222
+	OpExprCumProd   = 223
+	OpExprCumCount  = 224
+	OpExprDiff      = 225
+	OpExprPctChange = 226
+
+	// Extended aggregations (usable in Agg and Select)
+	OpExprQuantile      = 230
+	OpExprMode          = 231
+	OpExprApproxNUnique = 232
+	OpExprArgMin        = 233
+	OpExprArgMax        = 234
+	OpExprSkew          = 235
+	OpExprKurtosis      = 236
+	OpExprProduct       = 237
+	OpExprImplode       = 238
+	OpExprAggGroups     = 239
+
+	// User-defined functions (Go callbacks)
+	OpExprMapBatches = 240
+
 	// Error operation for fluent API error handling
 	OpError = 999
 )
 
 // Note: Sort direction and nulls ordering constants are defined directly
 // in sort.go using C.SORT_DIRECTION_* and C.NULLS_ORDERING_* constants
+
+// opcodeNames maps opcodes to display names for DescribeOps
+var opcodeNames = map[uint32]string{
+	OpNewEmpty:          "NewEmpty",
+	OpReadCsv:           "ReadCsv",
+	OpReadParquet:       "ReadParquet",
+	OpSelect:            "Select",
+	OpSelectExpr:        "SelectExpr",
+	OpCount:             "Count",
+	OpConcat:            "Concat",
+	OpWithColumn:        "WithColumn",
+	OpFilterExpr:        "FilterExpr",
+	OpGroupBy:           "GroupBy",
+	OpAddNullRow:        "AddNullRow",
+	OpCollect:           "Collect",
+	OpAgg:               "Agg",
+	OpSort:              "Sort",
+	OpLimit:             "Limit",
+	OpQuery:             "Query",
+	OpJoin:              "Join",
+	OpFromMemory:        "FromMemory",
+	OpDescribe:          "Describe",
+	OpNullCount:         "NullCount",
+	OpReadNdjson:        "ReadNdjson",
+	OpReadJson:          "ReadJson",
+	OpReadIpc:           "ReadIpc",
+	OpScanIpc:           "ScanIpc",
+	OpReadIpcStream:     "ReadIpcStream",
+	OpReadCsvBuffer:     "ReadCsvBuffer",
+	OpReadParquetBuffer: "ReadParquetBuffer",
+	OpReadNdjsonBuffer:  "ReadNdjsonBuffer",
+	OpRaisePanic:        "RaisePanic",
+	OpSqlContext:        "SqlContext",
+	OpDeserializePlan:   "DeserializePlan",
+
+	// Expression operations
+	OpExprColumn:          "ExprColumn",
+	OpExprLiteral:         "ExprLiteral",
+	OpExprAdd:             "ExprAdd",
+	OpExprSub:             "ExprSub",
+	OpExprMul:             "ExprMul",
+	OpExprDiv:             "ExprDiv",
+	OpExprGt:              "ExprGt",
+	OpExprLt:              "ExprLt",
+	OpExprEq:              "ExprEq",
+	OpExprAnd:             "ExprAnd",
+	OpExprOr:              "ExprOr",
+	OpExprNot:             "ExprNot",
+	OpExprSum:             "ExprSum",
+	OpExprMean:            "ExprMean",
+	OpExprMin:             "ExprMin",
+	OpExprMax:             "ExprMax",
+	OpExprStd:             "ExprStd",
+	OpExprVar:             "ExprVar",
+	OpExprMedian:          "ExprMedian",
+	OpExprFirst:           "ExprFirst",
+	OpExprLast:            "ExprLast",
+	OpExprNUnique:         "ExprNUnique",
+	OpExprCount:           "ExprCount",
+	OpExprCountNulls:      "ExprCountNulls",
+	OpExprIsNull:          "ExprIsNull",
+	OpExprIsNotNull:       "ExprIsNotNull",
+	OpExprAlias:           "ExprAlias",
+	OpExprStrLen:          "ExprStrLen",
+	OpExprStrContains:     "ExprStrContains",
+	OpExprStrStartsWith:   "ExprStrStartsWith",
+	OpExprStrEndsWith:     "ExprStrEndsWith",
+	OpExprStrToLowercase:  "ExprStrToLowercase",
+	OpExprStrToUppercase:  "ExprStrToUppercase",
+	OpExprSql:             "ExprSql",
+	OpExprStrSlice:        "ExprStrSlice",
+	OpExprStrReplace:      "ExprStrReplace",
+	OpExprStrSplit:        "ExprStrSplit",
+	OpExprStrLenBytes:     "ExprStrLenBytes",
+	OpExprStrStripChars:   "ExprStrStripChars",
+	OpExprStrStripStart:   "ExprStrStripStart",
+	OpExprStrStripEnd:     "ExprStrStripEnd",
+	OpExprStrStripPrefix:  "ExprStrStripPrefix",
+	OpExprStrStripSuffix:  "ExprStrStripSuffix",
+	OpExprStrReverse:      "ExprStrReverse",
+	OpExprStrHead:         "ExprStrHead",
+	OpExprStrTail:         "ExprStrTail",
+	OpExprStrPadStart:     "ExprStrPadStart",
+	OpExprStrPadEnd:       "ExprStrPadEnd",
+	OpExprStrZfill:        "ExprStrZfill",
+	OpExprOver:            "ExprOver",
+	OpExprRank:            "ExprRank",
+	OpExprDenseRank:       "ExprDenseRank",
+	OpExprRowNumber:       "ExprRowNumber",
+	OpExprLag:             "ExprLag",
+	OpExprLead:            "ExprLead",
+	OpExprWhen:            "ExprWhen",
+	OpExprThen:            "ExprThen",
+	OpExprOtherwise:       "ExprOtherwise",
+	OpExprCast:            "ExprCast",
+	OpExprRollingMean:     "ExprRollingMean",
+	OpExprRollingSum:      "ExprRollingSum",
+	OpExprRollingMin:      "ExprRollingMin",
+	OpExprRollingMax:      "ExprRollingMax",
+	OpExprRollingStd:      "ExprRollingStd",
+	OpExprRollingMedian:   "ExprRollingMedian",
+	OpExprRollingQuantile: "ExprRollingQuantile",
+	OpExprCumSum:          "ExprCumSum",
+	OpExprCumMin:          "ExprCumMin",
+	OpExprCumMax:          "ExprCumMax",
+	OpExprCumProd:         "ExprCumProd",
+	OpExprCumCount:        "ExprCumCount",
+	OpExprDiff:            "ExprDiff",
+	OpExprPctChange:       "ExprPctChange",
+	OpExprQuantile:        "ExprQuantile",
+	OpExprMode:            "ExprMode",
+	OpExprApproxNUnique:   "ExprApproxNUnique",
+	OpExprArgMin:          "ExprArgMin",
+	OpExprArgMax:          "ExprArgMax",
+	OpExprSkew:            "ExprSkew",
+	OpExprKurtosis:        "ExprKurtosis",
+	OpExprProduct:         "ExprProduct",
+	OpExprImplode:         "ExprImplode",
+	OpExprAggGroups:       "ExprAggGroups",
+	OpExprMapBatches:      "ExprMapBatches",
+	OpError:               "Error",
+}
+
+// opcodeName returns the display name of an opcode; expression opcodes are shown by number
+func opcodeName(opcode uint32) string {
+	if name, ok := opcodeNames[opcode]; ok {
+		return name
+	}
+	return fmt.Sprintf("Op(%d)", opcode)
+}
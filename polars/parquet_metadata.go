@@ -0,0 +1,68 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ParquetMetadata describes a Parquet file as recorded in its footer
+type ParquetMetadata struct {
+	NumRows          int64             `json:"num_rows"`           // Total rows across all row groups
+	Version          int32             `json:"version"`            // Parquet format version
+	CreatedBy        string            `json:"created_by"`         // Writer application, if recorded
+	RowGroups        []ParquetRowGroup `json:"row_groups"`         // Row groups in file order
+	KeyValueMetadata map[string]string `json:"key_value_metadata"` // Custom footer metadata
+}
+
+// ParquetRowGroup describes a single row group
+type ParquetRowGroup struct {
+	NumRows        int64                `json:"num_rows"`
+	TotalByteSize  int64                `json:"total_byte_size"` // Uncompressed size of all column chunks
+	CompressedSize int64                `json:"compressed_size"` // Compressed size of all column chunks
+	Columns        []ParquetColumnChunk `json:"columns"`
+}
+
+// ParquetColumnChunk describes one column within a row group
+// Min and Max hold the physical (stored) values, e.g. days since epoch for dates,
+// and are nil when the writer did not record statistics
+type ParquetColumnChunk struct {
+	Path             string  `json:"path"`          // Dotted path for nested columns
+	PhysicalType     string  `json:"physical_type"` // e.g. "Int64", "ByteArray"
+	Compression      string  `json:"compression"`   // e.g. "Snappy", "Zstd", "Uncompressed"
+	CompressedSize   int64   `json:"compressed_size"`
+	UncompressedSize int64   `json:"uncompressed_size"`
+	NumValues        int64   `json:"num_values"`
+	NullCount        *int64  `json:"null_count"`
+	Min              *string `json:"min"`
+	Max              *string `json:"max"`
+}
+
+// NumRowGroups returns the number of row groups in the file
+func (m *ParquetMetadata) NumRowGroups() int {
+	return len(m.RowGroups)
+}
+
+// ReadParquetMetadata reads the footer metadata of a single Parquet file
+// without scanning any data pages. Glob patterns are not supported.
+func ReadParquetMetadata(path string) (*ParquetMetadata, error) {
+	var errorCode C.int
+	var errorMessage *C.char
+
+	metadataPtr := C.read_parquet_metadata(makeRawStr(path), &errorCode, &errorMessage)
+	if metadataPtr == nil {
+		return nil, ffiError(errorCode, errorMessage, "failed to read parquet metadata")
+	}
+
+	metadataJSON := C.GoString(metadataPtr)
+	C.free_string(metadataPtr)
+
+	var metadata ParquetMetadata
+	if err := json.Unmarshal([]byte(metadataJSON), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode parquet metadata: %w", err)
+	}
+	return &metadata, nil
+}
@@ -0,0 +1,46 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"errors"
+	"runtime"
+	"unsafe"
+)
+
+// WriteParquetPartitioned writes an executed DataFrame as a Hive-partitioned Parquet dataset
+// Every distinct combination of partitionBy values becomes a directory such as
+// dir/year=2024/month=01/ holding the remaining columns; read it back with
+// ReadParquetWithOptions(dir, ParquetOptions{HivePartitioning: true, ...})
+// Files are numbered 00000000.parquet, 00000001.parquet, ... per directory, and writing to
+// an existing dataset appends new files: existing data is never overwritten.
+func (df *DataFrame) WriteParquetPartitioned(dir string, partitionBy ...string) error {
+	if df.handle.handle == 0 {
+		return errors.New("dataframe not executed - call Collect() first")
+	}
+	if len(partitionBy) == 0 {
+		return errors.New("WriteParquetPartitioned: at least one partition column is required")
+	}
+
+	// The RawStr array lives in Go memory and points at Go strings, so pin them for the call
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	columns := make([]C.RawStr, len(partitionBy))
+	for i, name := range partitionBy {
+		if len(name) > 0 {
+			pinner.Pin(unsafe.StringData(name))
+		}
+		columns[i] = makeRawStr(name)
+	}
+
+	var errorMessage *C.char
+	code := C.dataframe_write_parquet_partitioned(df.handle.handle, makeRawStr(dir),
+		&columns[0], C.size_t(len(columns)), &errorMessage)
+	runtime.KeepAlive(df)
+	if code != 0 {
+		return ffiError(code, errorMessage, "failed to write partitioned parquet dataset")
+	}
+	return nil
+}
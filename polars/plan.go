@@ -0,0 +1,106 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+const (
+	planFormat        = "firn.plan" // Identifies MarshalPlan output
+	planFormatVersion = 1           // Bumped when the envelope changes incompatibly
+)
+
+// serializedPlan is the versioned JSON envelope written by MarshalPlan
+type serializedPlan struct {
+	Format  string          `json:"format"`  // Always planFormat
+	Version int             `json:"version"` // planFormatVersion of the writer
+	Polars  string          `json:"polars"`  // Polars release that serialized Plan
+	Plan    json.RawMessage `json:"plan"`    // Polars logical plan
+}
+
+// MarshalPlan serializes the pending query as versioned JSON without running it
+// The result can be stored, or sent to another process, and turned back into a query
+// with UnmarshalPlan. Collected DataFrames the query reads, including the other side of
+// a Join, are embedded with their data; file scans keep only their paths, which must
+// resolve where the plan is collected. Plans that call Go functions (MapBatches,
+// MapElements) cannot be serialized, and neither can scans with static Cloud credentials,
+// which would be written out in plain text; scans that use the default credential chain
+// keep their endpoint and region only.
+func (df *DataFrame) MarshalPlan() ([]byte, error) {
+	plan, err := df.asSubPlan()
+	if err != nil {
+		return nil, fmt.Errorf("MarshalPlan: %w", err)
+	}
+	defer releaseHandleRefs(plan.refs())
+	if plan.secret() {
+		return nil, errors.New("MarshalPlan: plan reads with static cloud credentials, " +
+			"which would be serialized; use the default credential chain instead")
+	}
+
+	var errorCode C.int
+	var errorMessage *C.char
+	planPtr := C.serialize_plan(plan.toC(), &errorCode, &errorMessage)
+	if planPtr == nil {
+		return nil, ffiError(errorCode, errorMessage, "failed to serialize plan")
+	}
+
+	planJSON := C.GoString(planPtr)
+	C.free_string(planPtr)
+	return json.Marshal(serializedPlan{
+		Format:  planFormat,
+		Version: planFormatVersion,
+		Polars:  polarsVersion(),
+		Plan:    json.RawMessage(planJSON),
+	})
+}
+
+// UnmarshalPlan returns a lazy DataFrame for a plan written by MarshalPlan
+// Polars plans are tied to the Polars release that wrote them, so a plan from a build
+// with another release is rejected here. Errors inside the plan itself surface when the
+// result is collected; further operations can be chained onto it like any other query.
+func UnmarshalPlan(data []byte) (*DataFrame, error) {
+	var envelope serializedPlan
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("UnmarshalPlan: %w", err)
+	}
+	if envelope.Format != planFormat {
+		return nil, fmt.Errorf("UnmarshalPlan: not a serialized plan (format %q)", envelope.Format)
+	}
+	if envelope.Version < 1 || envelope.Version > planFormatVersion {
+		return nil, fmt.Errorf("UnmarshalPlan: unsupported plan version %d (supported: 1 to %d)",
+			envelope.Version, planFormatVersion)
+	}
+	if version := polarsVersion(); envelope.Polars != version {
+		return nil, fmt.Errorf("UnmarshalPlan: plan was serialized with Polars %s, this build uses Polars %s",
+			envelope.Polars, version)
+	}
+	if len(envelope.Plan) == 0 {
+		return nil, errors.New("UnmarshalPlan: plan is missing")
+	}
+
+	planJSON := string(envelope.Plan)
+	op := Operation{
+		opcode: OpDeserializePlan,
+		args: func() unsafe.Pointer {
+			return unsafe.Pointer(&C.PlanArgs{
+				json: makeRawStr(planJSON), // planJSON captured by closure
+			})
+		},
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}, nil
+}
+
+// polarsVersion returns the Polars release this library was built with
+func polarsVersion() string {
+	return C.GoString(C.plan_polars_version())
+}
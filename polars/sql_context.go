@@ -0,0 +1,154 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"unsafe"
+)
+
+// SQLContext runs SQL over several DataFrames registered under table names
+// Registration is lazy: a registered DataFrame's pending operations run as part of every
+// query that reads it, so inputs need not be collected first, and the optimizer sees
+// the whole plan across tables. A SQLContext is safe for concurrent use.
+//
+// Example:
+//
+//	ctx := polars.NewSQLContext()
+//	ctx.Register("orders", polars.ReadParquet("orders.parquet"))
+//	ctx.Register("users", polars.ReadCSV("users.csv"))
+//	result, err := ctx.Execute(`SELECT u.name, SUM(o.total) AS spent
+//	    FROM orders o JOIN users u ON o.user_id = u.id GROUP BY u.name`).Collect()
+type SQLContext struct {
+	mu     sync.Mutex
+	tables map[string]subPlan
+}
+
+// NewSQLContext creates a SQLContext with no tables
+func NewSQLContext() *SQLContext {
+	return &SQLContext{tables: make(map[string]subPlan)}
+}
+
+// Register makes df available to queries as table name, replacing any table of that name
+// df is snapshotted: builder calls made on it afterwards do not change the table, and
+// releasing it does not free data the table still uses.
+func (ctx *SQLContext) Register(name string, df *DataFrame) error {
+	if name == "" {
+		return errors.New("Register: table name cannot be empty")
+	}
+	if df == nil {
+		return fmt.Errorf("Register: DataFrame for table %q cannot be nil", name)
+	}
+	plan, err := df.asSubPlan()
+	if err != nil {
+		return fmt.Errorf("Register: table %q: %w", name, err)
+	}
+
+	ctx.mu.Lock()
+	previous, replaced := ctx.tables[name]
+	ctx.tables[name] = plan
+	ctx.mu.Unlock()
+
+	if replaced {
+		releaseHandleRefs(previous.refs())
+	}
+	return nil
+}
+
+// Unregister removes table name; queries already built with Execute keep reading it
+func (ctx *SQLContext) Unregister(name string) {
+	ctx.mu.Lock()
+	plan, ok := ctx.tables[name]
+	delete(ctx.tables, name)
+	ctx.mu.Unlock()
+
+	if ok {
+		releaseHandleRefs(plan.refs())
+	}
+}
+
+// Tables returns the registered table names in sorted order
+func (ctx *SQLContext) Tables() []string {
+	ctx.mu.Lock()
+	defer ctx.mu.Unlock()
+
+	names := make([]string, 0, len(ctx.tables))
+	for name := range ctx.tables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Execute returns a lazy DataFrame for a SQL query over the registered tables
+// Tables registered or unregistered later do not affect it. Errors in the SQL, such as
+// an unknown table, surface when the result is collected.
+func (ctx *SQLContext) Execute(sql string) *DataFrame {
+	type sqlTable struct {
+		name string
+		plan subPlan
+	}
+
+	ctx.mu.Lock()
+	tables := make([]sqlTable, 0, len(ctx.tables))
+	for name, plan := range ctx.tables {
+		// The query holds its own references, so Unregister cannot free its inputs
+		tables = append(tables, sqlTable{name: name, plan: plan.retain()})
+	}
+	ctx.mu.Unlock()
+	slices.SortFunc(tables, func(a, b sqlTable) int { return cmp.Compare(a.name, b.name) })
+
+	var refs []*handleRef
+	secret := false
+	for _, table := range tables {
+		refs = append(refs, table.plan.refs()...)
+		secret = secret || table.plan.secret()
+	}
+
+	op := Operation{
+		opcode: OpSqlContext,
+		args: func() unsafe.Pointer {
+			cTables := make([]C.SqlTable, len(tables))
+			for i, table := range tables {
+				cTables[i] = C.SqlTable{
+					name: makeRawStr(table.name),
+					plan: table.plan.toC(),
+				}
+			}
+
+			args := &C.SqlContextArgs{
+				count: C.size_t(len(cTables)),
+				sql:   makeRawStr(sql),
+			}
+			if len(cTables) > 0 {
+				args.tables = &cTables[0]
+			}
+			return unsafe.Pointer(args)
+		},
+		refs:   refs,
+		secret: secret,
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// Release unregisters every table, dropping the context's references to their data
+func (ctx *SQLContext) Release() {
+	ctx.mu.Lock()
+	tables := ctx.tables
+	ctx.tables = make(map[string]subPlan)
+	ctx.mu.Unlock()
+
+	for _, plan := range tables {
+		releaseHandleRefs(plan.refs())
+	}
+}
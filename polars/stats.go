@@ -0,0 +1,109 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// MemoryStats is a snapshot of the Rust memory the process holds through DataFrame handles
+type MemoryStats struct {
+	LiveHandles int   // Handle references not yet released, as listed by LiveHandles
+	DataFrames  int   // Distinct Rust DataFrames those references keep alive
+	Bytes       int64 // Sum of their EstimatedSize
+}
+
+// Stats reports how many handles are live and how much data they hold
+// Bytes counts column buffers shared between DataFrames (e.g. a collected frame and a
+// Select of it) once per DataFrame, so it can overstate the memory actually in use.
+func Stats() MemoryStats {
+	// Retain each DataFrame under the lock so none is freed while Rust sizes it, but size
+	// them after unlocking so handles can be created and released in the meantime
+	handleRegistry.Lock()
+	stats := MemoryStats{LiveHandles: len(handleRegistry.live)}
+	var handles []C.uintptr_t
+	counted := make(map[C.uintptr_t]bool)
+	for _, h := range handleRegistry.live {
+		if counted[h.handle] {
+			continue // Another reference to the same DataFrame
+		}
+		counted[h.handle] = true
+		C.retain_dataframe(h.handle)
+		handles = append(handles, h.handle)
+	}
+	handleRegistry.Unlock()
+
+	stats.DataFrames = len(handles)
+	for _, handle := range handles {
+		stats.Bytes += int64(C.dataframe_estimated_size(handle, nil, nil))
+		C.release_dataframe(handle)
+	}
+	return stats
+}
+
+// maxThreadsVar is the environment variable Polars sizes its pool from when it starts
+const maxThreadsVar = "POLARS_MAX_THREADS"
+
+// threadPoolMu makes setting maxThreadsVar and starting the pool one step
+var threadPoolMu sync.Mutex
+
+// SetThreadPoolSize limits Polars to n worker threads
+// Polars starts its pool on first use and cannot resize it afterwards, so call this before
+// any DataFrame is collected, e.g. at the top of main; it fails with ErrInvalidOperation
+// once the pool runs with a different size. It sets POLARS_MAX_THREADS with os.Setenv,
+// which C code reading the environment on other threads may race with, so call it
+// before starting goroutines that use this package. Setting POLARS_MAX_THREADS in the
+// process environment has the same effect without code changes.
+func SetThreadPoolSize(n int) error {
+	if n <= 0 {
+		return fmt.Errorf("SetThreadPoolSize: size must be positive, got %d", n)
+	}
+
+	threadPoolMu.Lock()
+	defer threadPoolMu.Unlock()
+
+	previous, set := os.LookupEnv(maxThreadsVar)
+	if err := os.Setenv(maxThreadsVar, strconv.Itoa(n)); err != nil {
+		return fmt.Errorf("SetThreadPoolSize: %w", err)
+	}
+	size, err := threadPoolInit()
+	if err == nil && size == n {
+		return nil
+	}
+
+	// The pool was already running; leave the environment as it was
+	if set {
+		os.Setenv(maxThreadsVar, previous)
+	} else {
+		os.Unsetenv(maxThreadsVar)
+	}
+	if err != nil {
+		return err
+	}
+	return &Error{
+		Code:    codeInvalidOperation,
+		Message: fmt.Sprintf("Polars thread pool already started with %d threads", size),
+	}
+}
+
+// ThreadPoolSize returns the number of Polars worker threads, starting the pool if needed
+func ThreadPoolSize() int {
+	size, _ := threadPoolInit()
+	return size
+}
+
+// threadPoolInit starts the Polars thread pool if needed and returns its size
+func threadPoolInit() (int, error) {
+	var errorCode C.int
+	var errorMessage *C.char
+	size := C.thread_pool_init(&errorCode, &errorMessage)
+	if size == 0 {
+		return 0, ffiError(errorCode, errorMessage, "failed to start thread pool")
+	}
+	return int(size), nil
+}
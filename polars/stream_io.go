@@ -0,0 +1,163 @@
+package polars
+
+/*
+#include "firn.h"
+*/
+import "C"
+import (
+	"context"
+	"errors"
+	"io"
+	"runtime"
+	"unsafe"
+)
+
+// Output formats for dataframe_to_buffer
+const (
+	bufferFormatCSV     = 0
+	bufferFormatParquet = 1
+)
+
+// ReadCSVFrom reads CSV from r, e.g. an HTTP response body
+// r is read to EOF immediately and the bytes are handed to Polars without a temp file;
+// parsing happens when the DataFrame is executed. WithGlob, Cloud and IncludeFilePaths are ignored.
+func ReadCSVFrom(r io.Reader, options CSVOptions) *DataFrame {
+	if err := validateCSVOptions(options); err != nil {
+		return NewDataFrame().appendErrOpf("ReadCSVFrom: %v", err)
+	}
+	data, err := readAllNonEmpty(r)
+	if err != nil {
+		return NewDataFrame().appendErrOpf("ReadCSVFrom: %w", err)
+	}
+	overrideNames := sortedOverrideNames(options.SchemaOverrides)
+
+	op := Operation{
+		opcode: OpReadCsvBuffer,
+		args: func() unsafe.Pointer {
+			return unsafe.Pointer(&C.ReadCsvBufferArgs{
+				buffer:  makeBufferArgs(data), // data captured by closure
+				options: newReadCsvArgs("", options, overrideNames),
+			})
+		},
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// ReadParquetFrom reads a Parquet file of size bytes from r, e.g. an S3 object or os.File
+// Parquet keeps its metadata in the footer, so the whole file is loaded into memory
+// before it is handed to Polars
+func ReadParquetFrom(r io.ReaderAt, size int64) *DataFrame {
+	if size <= 0 {
+		return NewDataFrame().appendErrOpf("ReadParquetFrom: size must be positive, got %d", size)
+	}
+	data, err := readAllNonEmpty(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return NewDataFrame().appendErrOpf("ReadParquetFrom: %w", err)
+	}
+	if int64(len(data)) != size {
+		return NewDataFrame().appendErrOpf("ReadParquetFrom: read %d of %d bytes", len(data), size)
+	}
+
+	op := Operation{
+		opcode: OpReadParquetBuffer,
+		args: func() unsafe.Pointer {
+			args := makeBufferArgs(data) // data captured by closure
+			return unsafe.Pointer(&args)
+		},
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// ReadNDJSONFrom reads newline-delimited JSON from r
+// r is read to EOF immediately; parsing happens when the DataFrame is executed
+func ReadNDJSONFrom(r io.Reader) *DataFrame {
+	data, err := readAllNonEmpty(r)
+	if err != nil {
+		return NewDataFrame().appendErrOpf("ReadNDJSONFrom: %w", err)
+	}
+
+	op := Operation{
+		opcode: OpReadNdjsonBuffer,
+		args: func() unsafe.Pointer {
+			args := makeBufferArgs(data) // data captured by closure
+			return unsafe.Pointer(&args)
+		},
+	}
+
+	return &DataFrame{
+		handle:     C.PolarsHandle{handle: C.uintptr_t(0), context_type: C.uint32_t(0)}, // Lazy - no handle yet
+		operations: []Operation{withCallSite(op)},
+	}
+}
+
+// WriteParquetTo writes an executed DataFrame to w as a Parquet file
+func (df *DataFrame) WriteParquetTo(w io.Writer) error {
+	return df.writeBufferTo(context.Background(), w, bufferFormatParquet, "WriteParquetTo")
+}
+
+// WriteParquetToContext is WriteParquetTo that stops with ctx.Err() once ctx is done
+// Encoding stops at its next write; writing to w stops between chunks, not mid-Write
+func (df *DataFrame) WriteParquetToContext(ctx context.Context, w io.Writer) error {
+	return df.writeBufferTo(ctx, w, bufferFormatParquet, "WriteParquetToContext")
+}
+
+// WriteCSVTo writes an executed DataFrame to w as CSV with a header row
+func (df *DataFrame) WriteCSVTo(w io.Writer) error {
+	return df.writeBufferTo(context.Background(), w, bufferFormatCSV, "WriteCSVTo")
+}
+
+// WriteCSVToContext is WriteCSVTo that stops with ctx.Err() once ctx is done
+// Encoding stops at its next write; writing to w stops between chunks, not mid-Write
+func (df *DataFrame) WriteCSVToContext(ctx context.Context, w io.Writer) error {
+	return df.writeBufferTo(ctx, w, bufferFormatCSV, "WriteCSVToContext")
+}
+
+// writeBufferTo serializes the DataFrame on the Rust side and copies the result to w
+func (df *DataFrame) writeBufferTo(ctx context.Context, w io.Writer, format int, name string) error {
+	if df.handle.handle == 0 {
+		return errors.New("dataframe not executed - call Collect() first")
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	buffer, length, err := encodeCancellable(ctx, "failed to serialize dataframe",
+		func(token C.uintptr_t, length *C.size_t, errorCode *C.int, errorMessage **C.char) *C.uint8_t {
+			return C.dataframe_to_buffer(df.handle.handle, C.uint8_t(format), token, length, errorCode, errorMessage)
+		})
+	runtime.KeepAlive(df)
+	if err != nil {
+		return err
+	}
+	defer C.free_buffer(buffer, length)
+
+	return writeContext(ctx, w, unsafe.Slice((*byte)(unsafe.Pointer(buffer)), int(length)), name)
+}
+
+// readAllNonEmpty reads r to EOF and rejects empty input
+func readAllNonEmpty(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("input is empty")
+	}
+	return data, nil
+}
+
+// makeBufferArgs points BufferArgs at data, which must be non-empty
+func makeBufferArgs(data []byte) C.BufferArgs {
+	return C.BufferArgs{
+		data: (*C.uint8_t)(unsafe.Pointer(&data[0])),
+		len:  C.size_t(len(data)),
+	}
+}
@@ -0,0 +1,423 @@
+package polars
+
+/*
+#include "firn.h"
+
+extern int firnCallUDF(uintptr_t udf, SeriesView* input, UdfOutput* output);
+*/
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"runtime/cgo"
+	"strings"
+	"unsafe"
+)
+
+func init() {
+	C.register_udf_callback(C.UdfCallback(C.firnCallUDF))
+}
+
+// Series is one column of values passed to or returned from a MapBatches function
+// A Series received from Polars views Rust memory without copying: its slices and
+// strings are only valid until the function returns and must not be modified.
+type Series struct {
+	name           string
+	dtype          DataType
+	values         any    // Typed slice such as []int64 or []string
+	validity       []byte // Arrow validity bitmap, bit set = row is valid; nil when no row is null
+	validityOffset int    // Bit index of the first row in validity
+}
+
+// NewSeries creates a Series from a []int8 … []uint64, []float32, []float64, []bool or []string
+// The data type follows the slice type; any other slice fails the plan when returned.
+func NewSeries(name string, values any) Series {
+	return Series{name: name, dtype: dataTypeOf(values), values: values}
+}
+
+// WithNulls returns a copy of s where row i is null wherever valid[i] is false
+func (s Series) WithNulls(valid []bool) Series {
+	bitmap := make([]byte, (len(valid)+7)/8)
+	for i, ok := range valid {
+		if ok {
+			bitmap[i/8] |= 1 << (i % 8)
+		}
+	}
+	s.validity = bitmap
+	s.validityOffset = 0
+	return s
+}
+
+// Name returns the column name
+func (s Series) Name() string { return s.name }
+
+// DataType returns the type of the values
+func (s Series) DataType() DataType { return s.dtype }
+
+// Len returns the number of rows
+func (s Series) Len() int {
+	if s.dtype == 0 {
+		return 0
+	}
+	return reflect.ValueOf(s.values).Len()
+}
+
+// IsNull reports whether row i is null; its slot in Values holds an unspecified value
+func (s Series) IsNull(i int) bool {
+	if s.validity == nil {
+		return false
+	}
+	bit := s.validityOffset + i
+	return s.validity[bit/8]&(1<<(bit%8)) == 0
+}
+
+// Value returns row i as its Go element type (int64, float64, string, bool, ...), or nil if null
+func (s Series) Value(i int) any {
+	if s.IsNull(i) {
+		return nil
+	}
+	return reflect.ValueOf(s.values).Index(i).Interface()
+}
+
+// Values returns the typed slice backing s, e.g. []int64 for an Int64 column
+func (s Series) Values() any { return s.values }
+
+// Int64s returns the values of an Int64 series, or nil for other types
+func (s Series) Int64s() []int64 {
+	values, _ := s.values.([]int64)
+	return values
+}
+
+// Float64s returns the values of a Float64 series, or nil for other types
+func (s Series) Float64s() []float64 {
+	values, _ := s.values.([]float64)
+	return values
+}
+
+// Strings returns the values of a String series, or nil for other types
+func (s Series) Strings() []string {
+	values, _ := s.values.([]string)
+	return values
+}
+
+// Bools returns the values of a Boolean series, or nil for other types
+func (s Series) Bools() []bool {
+	values, _ := s.values.([]bool)
+	return values
+}
+
+// dataTypeOf maps a slice type accepted by NewSeries to its DataType, or 0
+func dataTypeOf(values any) DataType {
+	switch values.(type) {
+	case []int8:
+		return Int8
+	case []int16:
+		return Int16
+	case []int32:
+		return Int32
+	case []int64:
+		return Int64
+	case []uint8:
+		return UInt8
+	case []uint16:
+		return UInt16
+	case []uint32:
+		return UInt32
+	case []uint64:
+		return UInt64
+	case []float32:
+		return Float32
+	case []float64:
+		return Float64
+	case []bool:
+		return Boolean
+	case []string:
+		return String
+	default:
+		return 0
+	}
+}
+
+// MapBatches applies a Go function to the expression's values a whole column at a time
+// inside the lazy plan, instead of collecting, transforming in Go and reading back.
+// The result is cast to returnType, which also gives the planner the output schema.
+// Polars may call fn concurrently, once per batch (per group inside Agg). An error or
+// panic in fn fails the plan with ErrComputeError. Temporal inputs must be cast first.
+// Example: Col("amount").MapBatches(convertCurrency, polars.Float64).Alias("amount_usd")
+func (expr *ExprNode) MapBatches(fn func(Series) (Series, error), returnType DataType) *ExprNode {
+	if fn == nil {
+		return &ExprNode{ops: combine(expr.ops, single(errOp("MapBatches() function cannot be nil")))}
+	}
+
+	udf := newUDFHandle(fn)
+	return &ExprNode{
+		ops: combine(expr.ops, single(Operation{
+			opcode: OpExprMapBatches,
+			args: func() unsafe.Pointer {
+				// udf is captured here, so its handle lives as long as the operation
+				return unsafe.Pointer(&C.MapBatchesArgs{
+					udf:         C.uintptr_t(udf.handle),
+					return_type: C.uint32_t(returnType),
+				})
+			},
+		})),
+	}
+}
+
+// MapElements applies a Go function to each non-null value of the expression; nulls stay
+// null. fn receives the value as its Go type (int64, float64, string, bool, ...) and returns
+// a value convertible to returnType, or nil for null. It runs on top of MapBatches with one
+// call per row, so prefer MapBatches or a built-in expression on hot paths.
+// Example: Col("sku").MapElements(func(v any) (any, error) { return catalog[v.(string)], nil }, polars.String)
+func (expr *ExprNode) MapElements(fn func(value any) (any, error), returnType DataType) *ExprNode {
+	if fn == nil {
+		return &ExprNode{ops: combine(expr.ops, single(errOp("MapElements() function cannot be nil")))}
+	}
+	switch returnType & 0xFFFF_0000 {
+	case FamilyInteger, FamilyFloat, FamilyString, FamilyBoolean:
+	default:
+		return &ExprNode{ops: combine(expr.ops, single(errOpf("MapElements() unsupported return type %#x", uint32(returnType))))}
+	}
+
+	return expr.MapBatches(func(input Series) (Series, error) {
+		results := make([]any, input.Len())
+		for i := range results {
+			if input.IsNull(i) {
+				continue
+			}
+			value := input.Value(i)
+			if str, ok := value.(string); ok {
+				value = strings.Clone(str) // fn may keep it after the Rust buffer is freed
+			}
+			result, err := fn(value)
+			if err != nil {
+				return Series{}, fmt.Errorf("row %d: %w", i, err)
+			}
+			results[i] = result
+		}
+		return seriesFromValues(input.Name(), returnType, results)
+	}, returnType)
+}
+
+// seriesFromValues builds the widest Series of returnType's family from MapElements results;
+// Rust casts it to returnType itself
+func seriesFromValues(name string, returnType DataType, results []any) (Series, error) {
+	valid := make([]bool, len(results))
+	var values any
+	switch returnType & 0xFFFF_0000 {
+	case FamilyInteger:
+		ints := make([]int64, len(results))
+		for i, result := range results {
+			if result == nil {
+				continue
+			}
+			v := reflect.ValueOf(result)
+			switch {
+			case v.CanInt():
+				ints[i] = v.Int()
+			case v.CanUint():
+				ints[i] = int64(v.Uint())
+			default:
+				return Series{}, fmt.Errorf("row %d: cannot use %T as an integer", i, result)
+			}
+			valid[i] = true
+		}
+		values = ints
+	case FamilyFloat:
+		floats := make([]float64, len(results))
+		for i, result := range results {
+			if result == nil {
+				continue
+			}
+			v := reflect.ValueOf(result)
+			switch {
+			case v.CanFloat():
+				floats[i] = v.Float()
+			case v.CanInt():
+				floats[i] = float64(v.Int())
+			case v.CanUint():
+				floats[i] = float64(v.Uint())
+			default:
+				return Series{}, fmt.Errorf("row %d: cannot use %T as a float", i, result)
+			}
+			valid[i] = true
+		}
+		values = floats
+	case FamilyString:
+		strs := make([]string, len(results))
+		for i, result := range results {
+			if result == nil {
+				continue
+			}
+			s, ok := result.(string)
+			if !ok {
+				return Series{}, fmt.Errorf("row %d: cannot use %T as a string", i, result)
+			}
+			strs[i], valid[i] = s, true
+		}
+		values = strs
+	default: // FamilyBoolean, checked by MapElements
+		bools := make([]bool, len(results))
+		for i, result := range results {
+			if result == nil {
+				continue
+			}
+			b, ok := result.(bool)
+			if !ok {
+				return Series{}, fmt.Errorf("row %d: cannot use %T as a bool", i, result)
+			}
+			bools[i], valid[i] = b, true
+		}
+		values = bools
+	}
+	return NewSeries(name, values).WithNulls(valid), nil
+}
+
+// udfHandle owns the cgo.Handle Rust passes back to firnCallUDF; the handle is deleted
+// once no operation that captured it is reachable
+type udfHandle struct {
+	handle cgo.Handle
+}
+
+func newUDFHandle(fn func(Series) (Series, error)) *udfHandle {
+	udf := &udfHandle{handle: cgo.NewHandle(fn)}
+	runtime.AddCleanup(udf, func(h cgo.Handle) { h.Delete() }, udf.handle)
+	return udf
+}
+
+// firnCallUDF runs the Go function of a MapBatches operation on one batch
+// Rust calls it through register_udf_callback, often from Polars worker threads.
+//
+//export firnCallUDF
+func firnCallUDF(udf C.uintptr_t, input *C.SeriesView, output *C.UdfOutput) (status C.int) {
+	// A panic must not unwind into Rust; report it like a returned error
+	defer func() {
+		if r := recover(); r != nil {
+			status = udfError(output, fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	fn := cgo.Handle(udf).Value().(func(Series) (Series, error))
+	result, err := fn(seriesFromView(input))
+	if err != nil {
+		return udfError(output, err)
+	}
+
+	var pinner runtime.Pinner
+	defer pinner.Unpin()
+	view, err := result.toView(&pinner)
+	if err != nil {
+		return udfError(output, err)
+	}
+	return C.udf_output_set(output, &view)
+}
+
+// udfError hands err's message to Rust, which fails the plan with it
+func udfError(output *C.UdfOutput, err error) C.int {
+	message := err.Error()
+	C.udf_output_error(output, makeRawStr(message))
+	return 1
+}
+
+// seriesFromView wraps a batch from Rust without copying its buffers
+func seriesFromView(view *C.SeriesView) Series {
+	n := int(view.len)
+	s := Series{
+		name:  C.GoStringN(view.name.data, C.int(view.name.len)),
+		dtype: DataType(view.dtype),
+	}
+	if view.validity != nil {
+		s.validityOffset = int(view.validity_offset)
+		s.validity = unsafe.Slice((*byte)(unsafe.Pointer(view.validity)), (s.validityOffset+n+7)/8)
+	}
+
+	values := unsafe.Pointer(view.values)
+	switch s.dtype {
+	case Int8:
+		s.values = viewSlice[int8](values, n)
+	case Int16:
+		s.values = viewSlice[int16](values, n)
+	case Int32:
+		s.values = viewSlice[int32](values, n)
+	case Int64:
+		s.values = viewSlice[int64](values, n)
+	case UInt8:
+		s.values = viewSlice[uint8](values, n)
+	case UInt16:
+		s.values = viewSlice[uint16](values, n)
+	case UInt32:
+		s.values = viewSlice[uint32](values, n)
+	case UInt64:
+		s.values = viewSlice[uint64](values, n)
+	case Float32:
+		s.values = viewSlice[float32](values, n)
+	case Float64:
+		s.values = viewSlice[float64](values, n)
+	case Boolean:
+		s.values = viewSlice[bool](values, n) // Rust passes one 0/1 byte per value
+	case String:
+		offsets := viewSlice[int64](values, n+1)
+		strs := make([]string, n)
+		for i := range strs {
+			if length := offsets[i+1] - offsets[i]; length > 0 {
+				strs[i] = unsafe.String((*byte)(unsafe.Add(unsafe.Pointer(view.data), offsets[i])), length)
+			}
+		}
+		s.values = strs
+	}
+	return s
+}
+
+// viewSlice borrows n values of T from Rust memory; Rust passes null for empty buffers
+func viewSlice[T any](values unsafe.Pointer, n int) []T {
+	if values == nil || n == 0 {
+		return []T{}
+	}
+	return unsafe.Slice((*T)(values), n)
+}
+
+// toView describes s for udf_output_set, which copies it before returning
+// pinner keeps the Go buffers the view points to in place until then.
+func (s Series) toView(pinner *runtime.Pinner) (C.SeriesView, error) {
+	if s.dtype == 0 {
+		return C.SeriesView{}, fmt.Errorf("unsupported series values %T", s.values)
+	}
+	n := s.Len()
+	view := C.SeriesView{
+		name:  makeRawStr(s.name),
+		dtype: C.uint32_t(s.dtype),
+		len:   C.size_t(n),
+	}
+	pinned(pinner, unsafe.Pointer(unsafe.StringData(s.name)))
+
+	if strs, ok := s.values.([]string); ok {
+		offsets := make([]int64, n+1)
+		var data []byte
+		for i, str := range strs {
+			data = append(data, str...)
+			offsets[i+1] = int64(len(data))
+		}
+		view.values = pinned(pinner, unsafe.Pointer(unsafe.SliceData(offsets)))
+		view.data = (*C.uint8_t)(pinned(pinner, unsafe.Pointer(unsafe.SliceData(data))))
+	} else {
+		view.values = pinned(pinner, reflect.ValueOf(s.values).UnsafePointer())
+	}
+
+	if s.validity != nil {
+		if len(s.validity)*8 < s.validityOffset+n {
+			return C.SeriesView{}, fmt.Errorf("series %q has %d rows but validity for %d", s.name, n, len(s.validity)*8-s.validityOffset)
+		}
+		view.validity = (*C.uint8_t)(pinned(pinner, unsafe.Pointer(unsafe.SliceData(s.validity))))
+		view.validity_offset = C.size_t(s.validityOffset)
+	}
+	return view, nil
+}
+
+// pinned pins the Go object p points into, if any, and returns p
+func pinned(pinner *runtime.Pinner, p unsafe.Pointer) unsafe.Pointer {
+	if p != nil {
+		pinner.Pin(p)
+	}
+	return p
+}
@@ -0,0 +1,39 @@
+//! Records the resolved polars version for plan.rs, which stamps it on serialized plans
+//! Polars only reads plans written by the same release, so the version must come from
+//! Cargo.lock rather than the version requirement in Cargo.toml.
+
+use std::env;
+use std::fs;
+use std::path::PathBuf;
+
+fn main() {
+    let manifest_dir = PathBuf::from(env::var("CARGO_MANIFEST_DIR").expect("CARGO_MANIFEST_DIR is set by cargo"));
+
+    // The lock file sits in the workspace root, which may be above this crate
+    let lock_path = manifest_dir
+        .ancestors()
+        .map(|dir| dir.join("Cargo.lock"))
+        .find(|path| path.is_file())
+        .expect("Cargo.lock not found; cargo writes it before running build scripts");
+    println!("cargo:rerun-if-changed={}", lock_path.display());
+
+    let lock = fs::read_to_string(&lock_path).expect("Cargo.lock is readable");
+    let version = polars_version(&lock).expect("Cargo.lock has no polars package");
+    println!("cargo:rustc-env=FIRN_POLARS_VERSION={}", version);
+}
+
+/// Version of the `polars` package in a Cargo.lock
+fn polars_version(lock: &str) -> Option<&str> {
+    lock.split("[[package]]").find_map(|package| {
+        let mut name = None;
+        let mut version = None;
+        for line in package.lines() {
+            if let Some(value) = line.strip_prefix("name = ") {
+                name = Some(value.trim_matches('"'));
+            } else if let Some(value) = line.strip_prefix("version = ") {
+                version = Some(value.trim_matches('"'));
+            }
+        }
+        (name == Some("polars")).then_some(version).flatten()
+    })
+}
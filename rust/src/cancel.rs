@@ -0,0 +1,136 @@
+use crate::{execute_operations, FfiResult, Operation, PolarsHandle, ERROR_CANCELLED};
+use polars::prelude::{DataFrame, LazyFrame, PolarsError, PolarsResult};
+use std::cell::Cell;
+use std::io::{self, Write};
+use std::ptr;
+use std::sync::atomic::{AtomicBool, Ordering};
+use std::time::Duration;
+
+/// How often a running collect checks the cancellation flag
+const CANCEL_POLL_INTERVAL: Duration = Duration::from_millis(5);
+
+/// Cancellation flag shared between a Go context watcher and a running execution
+pub struct CancelToken {
+    cancelled: AtomicBool,
+}
+
+thread_local! {
+    // Token of the execute_operations_cancellable call running on this thread, if any
+    static CURRENT_TOKEN: Cell<*const CancelToken> = const { Cell::new(ptr::null()) };
+}
+
+/// Allocate a cancellation token (release with cancel_token_free)
+#[no_mangle]
+pub extern "C" fn cancel_token_new() -> usize {
+    Box::into_raw(Box::new(CancelToken { cancelled: AtomicBool::new(false) })) as usize
+}
+
+/// Request cancellation; safe to call from any thread while an execution is running
+#[no_mangle]
+pub extern "C" fn cancel_token_cancel(token: usize) {
+    if token != 0 {
+        let token = unsafe { &*(token as *const CancelToken) };
+        token.cancelled.store(true, Ordering::SeqCst);
+    }
+}
+
+/// Release a cancellation token once no execution uses it any more
+#[no_mangle]
+pub extern "C" fn cancel_token_free(token: usize) {
+    if token != 0 {
+        unsafe {
+            let _ = Box::from_raw(token as *mut CancelToken);
+        }
+    }
+}
+
+/// execute_operations that stops with ERROR_CANCELLED once token is cancelled
+/// The flag is checked before every operation, and a Collect runs the query on the
+/// Polars thread pool so it can be interrupted mid-flight
+#[no_mangle]
+pub extern "C" fn execute_operations_cancellable(
+    polars_handle: PolarsHandle,
+    operations_ptr: *const Operation,
+    count: usize,
+    token: usize,
+) -> FfiResult {
+    with_cancel_token(token, || execute_operations(polars_handle, operations_ptr, count))
+}
+
+/// Run f with token (0 = none) as the cancellation token of this thread
+pub(crate) fn with_cancel_token<T>(token: usize, f: impl FnOnce() -> T) -> T {
+    // Clears the token again even if f unwinds
+    struct Reset;
+    impl Drop for Reset {
+        fn drop(&mut self) {
+            CURRENT_TOKEN.with(|current| current.set(ptr::null()));
+        }
+    }
+
+    CURRENT_TOKEN.with(|current| current.set(token as *const CancelToken));
+    let _reset = Reset;
+    f()
+}
+
+/// Whether the execution running on this thread has been cancelled
+pub(crate) fn is_cancelled() -> bool {
+    CURRENT_TOKEN.with(|current| {
+        let token = current.get();
+        !token.is_null() && unsafe { &*token }.cancelled.load(Ordering::SeqCst)
+    })
+}
+
+/// Error result for an execution stopped by its cancellation token
+pub(crate) fn cancelled_error() -> FfiResult {
+    FfiResult::error(ERROR_CANCELLED, "Execution was cancelled")
+}
+
+/// Collect a LazyFrame, interrupting the query if the current execution is cancelled
+/// Without a cancellation token this is a plain collect on the calling thread
+pub(crate) fn collect_cancellable(lazy_frame: LazyFrame) -> FfiResult {
+    match try_collect_cancellable(lazy_frame) {
+        Ok(df) => FfiResult::success(df),
+        Err(_) if is_cancelled() => cancelled_error(),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
+/// collect_cancellable for code that works with PolarsResult, like describe
+/// A cancelled collect returns an error; callers tell it apart with is_cancelled()
+pub(crate) fn try_collect_cancellable(lazy_frame: LazyFrame) -> PolarsResult<DataFrame> {
+    let has_token = CURRENT_TOKEN.with(|current| !current.get().is_null());
+    if !has_token {
+        return lazy_frame.collect();
+    }
+
+    let query = lazy_frame.collect_concurrently()?;
+    loop {
+        if let Some(result) = query.fetch() {
+            return result;
+        }
+        if is_cancelled() {
+            // Polars checks the stop flag between plan nodes; the query winds down in the background
+            query.cancel();
+            return Err(PolarsError::ComputeError("Execution was cancelled".into()));
+        }
+        std::thread::sleep(CANCEL_POLL_INTERVAL);
+    }
+}
+
+/// Writer that fails every write once the current execution is cancelled
+/// Encoders write as they go, so wrapping their output stops them at the next write
+pub(crate) struct CancellableWriter<W: Write>(pub W);
+
+impl<W: Write> Write for CancellableWriter<W> {
+    fn write(&mut self, buf: &[u8]) -> io::Result<usize> {
+        if is_cancelled() {
+            // Not ErrorKind::Interrupted, which write_all retries
+            return Err(io::Error::other("Execution was cancelled"));
+        }
+        self.0.write(buf)
+    }
+
+    fn flush(&mut self) -> io::Result<()> {
+        self.0.flush()
+    }
+}
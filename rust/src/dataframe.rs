@@ -1,16 +1,18 @@
 use crate::{
-    execute_expr_ops, ContextType, ExecutionContext, FfiResult, JoinArgs, JoinType, LimitArgs, 
+    execute_expr_ops, execute_subplan, ContextType, ExecutionContext, FfiResult, JoinArgs, JoinType, LimitArgs, 
     NullsOrdering, Operation, PolarsHandle, QueryArgs, RawStr, SortArgs, SortDirection, 
-    ERROR_INVALID_UTF8, ERROR_NULL_ARGS, ERROR_NULL_HANDLE, ERROR_POLARS_OPERATION,
-    FromMemoryArgs,
+    ERROR_INVALID_UTF8, ERROR_NULL_ARGS, ERROR_NULL_HANDLE, ERROR_POLARS_OPERATION, ERROR_INVALID_OPERATION,
+    FromMemoryArgs, SubPlan, bind_query_params, catch_panic, collect_cancellable, try_collect_cancellable,
+    is_cancelled, cancelled_error, polars_error_code, write_error, ERROR_PANIC,
 };
-use polars::prelude::{DataFrame, LazyFrame, LazyGroupBy, Expr, col, len, CsvWriter, 
+use polars::prelude::{DataFrame, LazyFrame, LazyGroupBy, Expr, col, len, lit, CsvWriter, 
     concat, UnionArgs, SortMultipleOptions, Series, Column, PolarsError, JoinArgs as PolarJoinArgs, JoinCoalesce,
-    IntoLazy, SerWriter};
+    IntoLazy, SerWriter, JsonWriter, JsonFormat, DataType, Null, PolarsResult, QuantileMethod};
 use polars_sql::SQLContext;
 use std::ffi::CString;
 use std::os::raw::{c_char, c_int};
 use std::ptr;
+use std::sync::Arc;
 
 /// Helper function to convert RawStr array to Vec<String>
 unsafe fn raw_str_array_to_vec(
@@ -44,8 +46,30 @@ pub struct SelectArgs {
 /// Arguments for concatenation operations
 #[repr(C)]
 pub struct ConcatArgs {
-    pub handles: *const usize, // Array of DataFrame handles
-    pub count: usize,          // Number of DataFrames to concatenate
+    pub inputs: *const SubPlan, // Array of input plans
+    pub count: usize,           // Number of inputs to concatenate
+}
+
+/// A table registered with a SQL context: a name and the plan that produces it
+#[repr(C)]
+pub struct SqlTable {
+    pub name: RawStr,
+    pub plan: SubPlan, // Executed lazily when the query is planned
+}
+
+/// Arguments for SQL over several named tables
+#[repr(C)]
+pub struct SqlContextArgs {
+    pub tables: *const SqlTable, // Array of registered tables (may be null when count is 0)
+    pub count: usize,            // Number of tables
+    pub sql: RawStr,
+}
+
+/// Arguments for describe operations
+#[repr(C)]
+pub struct DescribeArgs {
+    pub percentiles: *const f64, // Percentiles in [0, 1] (null = 25%, 50%, 75%)
+    pub percentile_count: usize, // Number of percentiles
 }
 
 /// Arguments for filter operations with expressions
@@ -100,7 +124,7 @@ pub fn dispatch_select(handle: PolarsHandle, context: &ExecutionContext) -> FfiR
         ContextType::LazyGroupBy => {
             // Invalid operation - cannot select on grouped data without aggregation
             FfiResult::error(
-                ERROR_POLARS_OPERATION,
+                ERROR_INVALID_OPERATION,
                 "Cannot call select() on grouped data. Call agg() first to resolve grouping.",
             )
         }
@@ -149,7 +173,7 @@ pub fn dispatch_group_by(handle: PolarsHandle, context: &ExecutionContext) -> Ff
         ContextType::LazyGroupBy => {
             // Invalid operation - cannot group already grouped data
             FfiResult::error(
-                ERROR_POLARS_OPERATION,
+                ERROR_INVALID_OPERATION,
                 "Cannot call group_by() on already grouped data.",
             )
         }
@@ -170,7 +194,7 @@ pub fn dispatch_agg(handle: PolarsHandle, context: &ExecutionContext) -> FfiResu
 
     if context_type != ContextType::LazyGroupBy {
         return FfiResult::error(
-            ERROR_POLARS_OPERATION,
+            ERROR_INVALID_OPERATION,
             "Agg() can only be called on LazyGroupBy. Use GroupBy() first.",
         );
     }
@@ -248,7 +272,7 @@ pub fn dispatch_sort(handle: PolarsHandle, context: &ExecutionContext) -> FfiRes
 
             match sorted_df {
                 Ok(result) => FfiResult::success(result),
-                Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &format!("Sort failed: {}", e)),
+                Err(e) => FfiResult::error(polars_error_code(&e), &format!("Sort failed: {}", e)),
             }
         }
         ContextType::LazyFrame => {
@@ -262,7 +286,7 @@ pub fn dispatch_sort(handle: PolarsHandle, context: &ExecutionContext) -> FfiRes
             FfiResult::success_lazy(sorted_lazy)
         }
         ContextType::LazyGroupBy => FfiResult::error(
-            ERROR_POLARS_OPERATION,
+            ERROR_INVALID_OPERATION,
             &format!(
                 "Cannot call sort() on {}. Call agg() first to resolve grouping.",
                 context_type.name()
@@ -302,7 +326,7 @@ pub fn dispatch_limit(handle: PolarsHandle, context: &ExecutionContext) -> FfiRe
             FfiResult::success_lazy(limited_lazy)
         }
         ContextType::LazyGroupBy => FfiResult::error(
-            ERROR_POLARS_OPERATION,
+            ERROR_INVALID_OPERATION,
             "Cannot call limit() on grouped data. Call agg() first to resolve grouping.",
         ),
     }
@@ -336,7 +360,7 @@ pub fn dispatch_count(handle: PolarsHandle) -> FfiResult {
         ContextType::LazyGroupBy => {
             // Invalid operation - cannot count grouped data without aggregation
             FfiResult::error(
-                ERROR_POLARS_OPERATION,
+                ERROR_INVALID_OPERATION,
                 "Cannot call count() on grouped data. Call agg() first to resolve grouping.",
             )
         }
@@ -346,32 +370,27 @@ pub fn dispatch_count(handle: PolarsHandle) -> FfiResult {
 /// Concatenate multiple DataFrames vertically (union)
 /// Note: _handle is unused as this follows functional style concat(df1, df2, df3)
 /// rather than method style df1.concat(df2, df3)
+/// Every input is a sub-plan resolved to a LazyFrame, so the union stays lazy
 pub fn dispatch_concat(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
     let args = unsafe { &*(context.operation_args as *const ConcatArgs) };
 
-    if args.handles.is_null() || args.count == 0 {
-        return FfiResult::error(ERROR_NULL_ARGS, "Concat handles cannot be null or empty");
+    if args.inputs.is_null() || args.count == 0 {
+        return FfiResult::error(ERROR_NULL_ARGS, "Concat inputs cannot be null or empty");
     }
 
-    // Convert handle array to DataFrames
-    let handles = unsafe { std::slice::from_raw_parts(args.handles, args.count) };
-    let mut dataframes = Vec::new();
+    let inputs = unsafe { std::slice::from_raw_parts(args.inputs, args.count) };
+    let mut lazy_frames = Vec::with_capacity(inputs.len());
 
-    for &handle in handles {
-        if handle == 0 {
-            return FfiResult::error(ERROR_NULL_HANDLE, "DataFrame handle cannot be null");
+    for input in inputs {
+        match execute_subplan(input) {
+            Ok(lazy_frame) => lazy_frames.push(lazy_frame),
+            Err(e) => return e,
         }
-        let df = unsafe { &*(handle as *const DataFrame) };
-        dataframes.push(df.clone().lazy());
     }
 
-    // Concatenate all DataFrames
-    match concat(dataframes, UnionArgs::default()) {
-        Ok(lazy_frame) => match lazy_frame.collect() {
-            Ok(df) => FfiResult::success(df),
-            Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
-        },
-        Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
+    match concat(lazy_frames, UnionArgs::default()) {
+        Ok(lazy_frame) => FfiResult::success_lazy(lazy_frame),
+        Err(e) => FfiResult::polars_error(&e),
     }
 }
 
@@ -415,7 +434,7 @@ pub fn dispatch_select_expr(handle: PolarsHandle, context: &ExecutionContext) ->
         ContextType::LazyGroupBy => {
             // Invalid operation - cannot select on grouped data without aggregation
             FfiResult::error(
-                ERROR_POLARS_OPERATION,
+                ERROR_INVALID_OPERATION,
                 "Cannot call select() on grouped data. Call agg() first to resolve grouping.",
             )
         }
@@ -462,7 +481,7 @@ pub fn dispatch_with_column(handle: PolarsHandle, context: &ExecutionContext) ->
         ContextType::LazyGroupBy => {
             // Invalid operation - cannot add columns to grouped data without aggregation
             FfiResult::error(
-                ERROR_POLARS_OPERATION,
+                ERROR_INVALID_OPERATION,
                 "Cannot call with_columns() on grouped data. Call agg() first to resolve grouping.",
             )
         }
@@ -515,7 +534,7 @@ pub fn dispatch_filter_expr(handle: PolarsHandle, context: &ExecutionContext) ->
         ContextType::LazyGroupBy => {
             // Invalid operation - cannot filter grouped data without aggregation
             FfiResult::error(
-                ERROR_POLARS_OPERATION,
+                ERROR_INVALID_OPERATION,
                 "Cannot call filter() on grouped data. Call agg() first to resolve grouping.",
             )
         }
@@ -530,10 +549,6 @@ pub fn dispatch_join(handle: PolarsHandle, context: &ExecutionContext) -> FfiRes
 
     let args = unsafe { &*(context.operation_args as *const JoinArgs) };
 
-    if args.other_handle == 0 {
-        return FfiResult::error(ERROR_NULL_HANDLE, "Right handle cannot be null");
-    }
-
     // Convert join type to Polars JoinType first to check if it's a cross join
     let join_how = match args.how {
         JoinType::Inner => polars::prelude::JoinType::Inner,
@@ -570,7 +585,7 @@ pub fn dispatch_join(handle: PolarsHandle, context: &ExecutionContext) -> FfiRes
         (left_on_exprs, right_on_exprs)
     };
 
-    // Get context types for both DataFrames
+    // Get context type of the left side
     let left_context_type = match handle.get_context_type() {
         Some(ct) => ct,
         None => return FfiResult::error(ERROR_POLARS_OPERATION, "Invalid left context type"),
@@ -586,89 +601,256 @@ pub fn dispatch_join(handle: PolarsHandle, context: &ExecutionContext) -> FfiRes
         None
     };
 
-    match left_context_type {
+    let left_lazy = match left_context_type {
         ContextType::DataFrame => {
-            // Both DataFrames - convert to LazyFrames for join, then collect
             let left_df = unsafe { &*(handle.handle as *const DataFrame) };
-            let right_df = unsafe { &*(args.other_handle as *const DataFrame) };
+            left_df.clone().lazy()
+        }
+        ContextType::LazyFrame => {
+            let left_lazy = unsafe { &*(handle.handle as *const LazyFrame) };
+            left_lazy.clone()
+        }
+        ContextType::LazyGroupBy => {
+            // Invalid operation - cannot join grouped data without aggregation
+            return FfiResult::error(
+                ERROR_INVALID_OPERATION,
+                "Cannot call join() on grouped data. Call agg() first to resolve grouping.",
+            );
+        }
+    };
 
-            let left_lazy = left_df.clone().lazy();
-            let right_lazy = right_df.clone().lazy();
+    // Resolve the right side lazily so the optimizer sees both inputs
+    let right_lazy = match execute_subplan(&args.other) {
+        Ok(lazy_frame) => lazy_frame,
+        Err(e) => return e,
+    };
 
-            // Create JoinArgs for Polars - use the builder pattern
-            let mut polars_join_args = PolarJoinArgs::new(join_how);
+    // Create JoinArgs for Polars - use the builder pattern
+    let mut polars_join_args = PolarJoinArgs::new(join_how);
 
-            if let Some(suffix_str) = suffix {
-                polars_join_args = polars_join_args.with_suffix(Some(suffix_str.into()));
-            }
+    if let Some(suffix_str) = suffix {
+        polars_join_args = polars_join_args.with_suffix(Some(suffix_str.into()));
+    }
 
-            if args.coalesce {
-                polars_join_args = polars_join_args.with_coalesce(JoinCoalesce::CoalesceColumns);
-            }
+    if args.coalesce {
+        polars_join_args = polars_join_args.with_coalesce(JoinCoalesce::CoalesceColumns);
+    }
 
-            // Perform the join
-            let joined_lazy = left_lazy.join(right_lazy, left_on_exprs, right_on_exprs, polars_join_args);
+    let joined_lazy = left_lazy.join(right_lazy, left_on_exprs, right_on_exprs, polars_join_args);
+    FfiResult::success_lazy(joined_lazy)
+}
 
-            // Collect to DataFrame
-            match joined_lazy.collect() {
-                Ok(result_df) => FfiResult::success(result_df),
-                Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
-            }
+/// Dispatch function for null count - number of nulls in every column
+pub fn dispatch_null_count(handle: PolarsHandle) -> FfiResult {
+    if handle.handle == 0 {
+        return FfiResult::error(ERROR_NULL_HANDLE, "Handle cannot be null");
+    }
+
+    let context_type = match handle.get_context_type() {
+        Some(ct) => ct,
+        None => return FfiResult::error(ERROR_POLARS_OPERATION, "Invalid context type"),
+    };
+
+    match context_type {
+        ContextType::DataFrame => {
+            let df = unsafe { &*(handle.handle as *const DataFrame) };
+            FfiResult::success_lazy(df.clone().lazy().null_count())
         }
         ContextType::LazyFrame => {
-            // Both LazyFrames - join directly
-            let left_lazy = unsafe { &*(handle.handle as *const LazyFrame) };
-            let right_lazy = unsafe { &*(args.other_handle as *const LazyFrame) };
-
-            // Create JoinArgs for Polars - use the builder pattern
-            let mut polars_join_args = PolarJoinArgs::new(join_how);
+            let lazy_frame = unsafe { &*(handle.handle as *const LazyFrame) };
+            FfiResult::success_lazy(lazy_frame.clone().null_count())
+        }
+        ContextType::LazyGroupBy => FfiResult::error(
+            ERROR_INVALID_OPERATION,
+            "Cannot call null_count() on grouped data. Call agg() first to resolve grouping.",
+        ),
+    }
+}
 
-            if let Some(suffix_str) = suffix {
-                polars_join_args = polars_join_args.with_suffix(Some(suffix_str.into()));
-            }
+/// Dispatch function for describe - summary statistics for every column
+/// All statistics are computed in a single select over the input and then
+/// reshaped into one row per statistic
+pub fn dispatch_describe(handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    if handle.handle == 0 {
+        return FfiResult::error(ERROR_NULL_HANDLE, "Handle cannot be null");
+    }
 
-            if args.coalesce {
-                polars_join_args = polars_join_args.with_coalesce(JoinCoalesce::CoalesceColumns);
-            }
+    let args = unsafe { &*(context.operation_args as *const DescribeArgs) };
 
-            // Perform the join
-            let joined_lazy = left_lazy.clone().join(right_lazy.clone(), left_on_exprs, right_on_exprs, polars_join_args);
+    let percentiles: Vec<f64> = if args.percentiles.is_null() || args.percentile_count == 0 {
+        vec![0.25, 0.5, 0.75]
+    } else {
+        unsafe { std::slice::from_raw_parts(args.percentiles, args.percentile_count) }.to_vec()
+    };
 
-            FfiResult::success_lazy(joined_lazy)
+    let lazy_frame = match handle.get_context_type() {
+        Some(ContextType::DataFrame) => {
+            let df = unsafe { &*(handle.handle as *const DataFrame) };
+            df.clone().lazy()
         }
-        ContextType::LazyGroupBy => {
-            // Invalid operation - cannot join grouped data without aggregation
-            FfiResult::error(
-                ERROR_POLARS_OPERATION,
-                "Cannot call join() on grouped data. Call agg() first to resolve grouping.",
+        Some(ContextType::LazyFrame) => {
+            let lazy_frame = unsafe { &*(handle.handle as *const LazyFrame) };
+            lazy_frame.clone()
+        }
+        Some(ContextType::LazyGroupBy) => {
+            return FfiResult::error(
+                ERROR_INVALID_OPERATION,
+                "Cannot call describe() on grouped data. Call agg() first to resolve grouping.",
             )
         }
+        None => return FfiResult::error(ERROR_POLARS_OPERATION, "Invalid context type"),
+    };
+
+    match describe_lazy_frame(lazy_frame, &percentiles) {
+        Ok(df) => FfiResult::success(df),
+        Err(_) if is_cancelled() => cancelled_error(),
+        Err(e) => FfiResult::polars_error(&e),
     }
 }
 
-/// Convert DataFrame to CSV string
-#[no_mangle]
-pub extern "C" fn dataframe_to_csv(handle: usize) -> *mut c_char {
-    if handle == 0 {
-        return ptr::null_mut();
+/// Format a percentile as a statistic label (0.25 -> "25%", 0.975 -> "97.5%")
+fn percentile_label(p: f64) -> String {
+    format!("{}%", (p * 100.0 * 1e6).round() / 1e6)
+}
+
+/// Build the describe table for a LazyFrame
+/// Numeric and boolean columns produce f64 statistics; other columns produce
+/// string statistics where mean/std/percentiles are null
+fn describe_lazy_frame(mut lazy_frame: LazyFrame, percentiles: &[f64]) -> PolarsResult<DataFrame> {
+    let schema = lazy_frame.collect_schema()?;
+
+    let mut stat_names: Vec<String> = vec![
+        "count".to_string(),
+        "null_count".to_string(),
+        "mean".to_string(),
+        "std".to_string(),
+        "min".to_string(),
+    ];
+    stat_names.extend(percentiles.iter().map(|p| percentile_label(*p)));
+    stat_names.push("max".to_string());
+
+    // One aliased expression per (column, statistic) so a single pass computes everything
+    let mut exprs: Vec<Expr> = Vec::with_capacity(schema.len() * stat_names.len());
+    let mut numeric_columns: Vec<bool> = Vec::with_capacity(schema.len());
+
+    for (col_idx, (name, dtype)) in schema.iter().enumerate() {
+        let numeric = dtype.is_numeric() || dtype.is_bool();
+        let orderable = numeric || dtype.is_temporal() || matches!(dtype, DataType::String);
+        numeric_columns.push(numeric);
+
+        let c = col(name.clone());
+        let mut column_exprs: Vec<Expr> = if numeric {
+            let values = c.clone().cast(DataType::Float64);
+            let mut v = vec![
+                c.clone().count().cast(DataType::Float64),
+                c.null_count().cast(DataType::Float64),
+                values.clone().mean(),
+                values.clone().std(1),
+                values.clone().min(),
+            ];
+            v.extend(
+                percentiles
+                    .iter()
+                    .map(|p| values.clone().quantile(lit(*p), QuantileMethod::Nearest)),
+            );
+            v.push(values.max());
+            v
+        } else {
+            let null_str = || lit(Null {}).cast(DataType::String);
+            let mut v = vec![
+                c.clone().count().cast(DataType::String),
+                c.clone().null_count().cast(DataType::String),
+                null_str(),
+                null_str(),
+                if orderable { c.clone().min().cast(DataType::String) } else { null_str() },
+            ];
+            v.extend(percentiles.iter().map(|_| null_str()));
+            v.push(if orderable { c.max().cast(DataType::String) } else { null_str() });
+            v
+        };
+
+        for (stat_idx, expr) in column_exprs.drain(..).enumerate() {
+            exprs.push(expr.alias(format!("{}:{}", col_idx, stat_idx)));
+        }
     }
 
-    let df = unsafe { &*(handle as *const DataFrame) };
+    let stats = if exprs.is_empty() {
+        DataFrame::empty()
+    } else {
+        // The scan behind describe can be long, so it stops with the execution's token
+        try_collect_cancellable(lazy_frame.select(exprs))?
+    };
 
-    let mut cursor = std::io::Cursor::new(Vec::new());
-    let mut df_clone = df.clone();
-    match CsvWriter::new(&mut cursor).finish(&mut df_clone) {
-        Ok(_) => {
-            let csv_data = cursor.into_inner();
-            match CString::new(csv_data) {
-                Ok(c_string) => c_string.into_raw(),
-                Err(_) => ptr::null_mut(),
+    // Reshape the single stats row into one row per statistic
+    let mut columns: Vec<Column> = Vec::with_capacity(schema.len() + 1);
+    columns.push(Column::new("statistic".into(), stat_names.clone()));
+
+    for (col_idx, (name, _)) in schema.iter().enumerate() {
+        let column = if numeric_columns[col_idx] {
+            let mut values: Vec<Option<f64>> = Vec::with_capacity(stat_names.len());
+            for stat_idx in 0..stat_names.len() {
+                let stat = stats.column(&format!("{}:{}", col_idx, stat_idx))?;
+                values.push(stat.f64()?.get(0));
+            }
+            Column::new(name.clone(), values)
+        } else {
+            let mut values: Vec<Option<String>> = Vec::with_capacity(stat_names.len());
+            for stat_idx in 0..stat_names.len() {
+                let stat = stats.column(&format!("{}:{}", col_idx, stat_idx))?;
+                values.push(stat.str()?.get(0).map(|s| s.to_string()));
             }
+            Column::new(name.clone(), values)
+        };
+        columns.push(column);
+    }
+
+    DataFrame::new(columns)
+}
+
+/// Borrow the DataFrame behind an executed handle for the string accessors below
+fn borrow_dataframe<'a>(handle: usize) -> Result<&'a DataFrame, (c_int, String)> {
+    if handle == 0 {
+        return Err((ERROR_NULL_HANDLE, "Handle cannot be null".to_string()));
+    }
+    Ok(unsafe { &*(handle as *const DataFrame) })
+}
+
+/// Run a string accessor, catching panics, and hand the text to C (free with free_string)
+/// Returns null with error_code and error_message set on failure
+fn string_result(
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+    body: impl FnOnce() -> Result<String, (c_int, String)>,
+) -> *mut c_char {
+    let result = catch_panic(body, |message| Err((ERROR_PANIC, message)));
+    match result.and_then(|text| {
+        CString::new(text).map_err(|_| (ERROR_POLARS_OPERATION, "Output contains interior NUL byte".to_string()))
+    }) {
+        Ok(c_string) => c_string.into_raw(),
+        Err((code, message)) => {
+            write_error(error_code, error_message, code, &message);
+            ptr::null_mut()
         }
-        Err(_) => ptr::null_mut(),
     }
 }
 
+/// Convert DataFrame to CSV string
+#[no_mangle]
+pub extern "C" fn dataframe_to_csv(
+    handle: usize,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut c_char {
+    string_result(error_code, error_message, || {
+        let mut df_clone = borrow_dataframe(handle)?.clone();
+        let mut buffer = Vec::new();
+        CsvWriter::new(&mut buffer)
+            .finish(&mut df_clone)
+            .map_err(|e| (polars_error_code(&e), e.to_string()))?;
+        String::from_utf8(buffer).map_err(|_| (ERROR_INVALID_UTF8, "CSV output is not valid UTF-8".to_string()))
+    })
+}
+
 /// Free C string memory
 #[no_mangle]
 pub extern "C" fn free_string(ptr: *mut c_char) {
@@ -681,40 +863,115 @@ pub extern "C" fn free_string(ptr: *mut c_char) {
 
 /// Convert DataFrame to string representation (tabular format)
 #[no_mangle]
-pub extern "C" fn dataframe_to_string(handle: usize) -> *mut c_char {
-    if handle == 0 {
-        return ptr::null_mut();
-    }
+pub extern "C" fn dataframe_to_string(
+    handle: usize,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut c_char {
+    string_result(error_code, error_message, || Ok(format!("{}", borrow_dataframe(handle)?)))
+}
 
-    let df = unsafe { &*(handle as *const DataFrame) };
-    let df_string = format!("{}", df);
+/// Convert DataFrame to a JSON array of row objects
+#[no_mangle]
+pub extern "C" fn dataframe_to_json(
+    handle: usize,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut c_char {
+    string_result(error_code, error_message, || {
+        let mut df_clone = borrow_dataframe(handle)?.clone();
+        let mut buffer = Vec::new();
+        JsonWriter::new(&mut buffer)
+            .with_json_format(JsonFormat::Json)
+            .finish(&mut df_clone)
+            .map_err(|e| (polars_error_code(&e), e.to_string()))?;
+        String::from_utf8(buffer).map_err(|_| (ERROR_INVALID_UTF8, "JSON output is not valid UTF-8".to_string()))
+    })
+}
 
-    match CString::new(df_string) {
-        Ok(c_string) => c_string.into_raw(),
-        Err(_) => ptr::null_mut(),
-    }
+/// Get DataFrame schema as a JSON array of {"name", "dtype"} objects
+#[no_mangle]
+pub extern "C" fn dataframe_schema(
+    handle: usize,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut c_char {
+    string_result(error_code, error_message, || {
+        let fields: Vec<serde_json::Value> = borrow_dataframe(handle)?
+            .get_columns()
+            .iter()
+            .map(|c| serde_json::json!({ "name": c.name().as_str(), "dtype": c.dtype().to_string() }))
+            .collect();
+        Ok(serde_json::Value::Array(fields).to_string())
+    })
 }
 
 /// Get DataFrame height (number of rows)
+/// Returns 0 with error_code and error_message set on failure
 #[no_mangle]
-pub extern "C" fn dataframe_height(handle: usize) -> usize {
-    if handle == 0 {
-        return 0;
+pub extern "C" fn dataframe_height(
+    handle: usize,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> usize {
+    let result = catch_panic(
+        || borrow_dataframe(handle).map(|df| df.height()),
+        |message| Err((ERROR_PANIC, message)),
+    );
+    match result {
+        Ok(height) => height,
+        Err((code, message)) => {
+            write_error(error_code, error_message, code, &message);
+            0
+        }
     }
-
-    let df = unsafe { &*(handle as *const DataFrame) };
-    df.height()
 }
 
-/// Release DataFrame memory
+/// Get the estimated heap size of a DataFrame in bytes
+/// Buffers shared with other DataFrames are counted in full
+/// Returns 0 with error_code and error_message set on failure
 #[no_mangle]
-pub extern "C" fn release_dataframe(handle: usize) -> c_int {
-    if handle != 0 {
-        unsafe {
-            let _ = Box::from_raw(handle as *mut DataFrame);
+pub extern "C" fn dataframe_estimated_size(
+    handle: usize,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> usize {
+    let result = catch_panic(
+        || borrow_dataframe(handle).map(|df| df.estimated_size()),
+        |message| Err((ERROR_PANIC, message)),
+    );
+    match result {
+        Ok(size) => size,
+        Err((code, message)) => {
+            write_error(error_code, error_message, code, &message);
+            0
         }
     }
-    0 // Return success
+}
+
+/// Take another reference to a DataFrame handle; each retain needs its own release
+#[no_mangle]
+pub extern "C" fn retain_dataframe(handle: usize) -> c_int {
+    if handle == 0 {
+        return ERROR_NULL_HANDLE;
+    }
+    unsafe { Arc::increment_strong_count(handle as *const DataFrame) };
+    0
+}
+
+/// Drop one reference to a DataFrame handle, freeing it when the last one goes
+/// Returns 0, or ERROR_PANIC if dropping the DataFrame panicked (the memory is then leaked)
+#[no_mangle]
+pub extern "C" fn release_dataframe(handle: usize) -> c_int {
+    catch_panic(
+        || {
+            if handle != 0 {
+                unsafe { Arc::decrement_strong_count(handle as *const DataFrame) };
+            }
+            0 // Return success
+        },
+        |_| ERROR_PANIC,
+    )
 }
 
 /// Benchmark helper - no-op function for measuring CGO overhead
@@ -746,10 +1003,7 @@ pub fn dispatch_collect(handle: PolarsHandle) -> FfiResult {
         ContextType::LazyFrame => {
             // Materialize LazyFrame into DataFrame
             let lazy_frame = unsafe { &*(handle.handle as *const LazyFrame) };
-            match lazy_frame.clone().collect() {
-                Ok(df) => FfiResult::success(df),
-                Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
-            }
+            collect_cancellable(lazy_frame.clone())
         }
         ContextType::LazyGroupBy => {
             // Invalid operation - cannot collect grouped data without aggregation
@@ -764,6 +1018,11 @@ pub fn dispatch_collect(handle: PolarsHandle) -> FfiResult {
     }
 }
 
+/// Panic on purpose so tests can exercise the FFI panic guard
+pub fn dispatch_raise_panic() -> FfiResult {
+    panic!("panic raised for testing")
+}
+
 pub fn dispatch_add_null_row(handle: PolarsHandle) -> FfiResult {
     if handle.handle == 0 {
         return FfiResult::error(ERROR_NULL_HANDLE, "Handle cannot be null");
@@ -791,20 +1050,20 @@ pub fn dispatch_add_null_row(handle: PolarsHandle) -> FfiResult {
 
             let null_series = match null_series {
                 Ok(series) => series,
-                Err(e) => return FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
+                Err(e) => return FfiResult::polars_error(&e),
             };
 
             // Convert Series to Column for DataFrame constructor
             let null_columns: Vec<Column> = null_series.into_iter().map(|s| s.into()).collect();
             let null_df = match DataFrame::new(null_columns) {
                 Ok(df) => df,
-                Err(e) => return FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
+                Err(e) => return FfiResult::polars_error(&e),
             };
 
             // Concatenate the original DataFrame with the null row
             match df.clone().vstack(&null_df) {
                 Ok(result_df) => FfiResult::success(result_df),
-                Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
+                Err(e) => FfiResult::polars_error(&e),
             }
         }
         ContextType::LazyFrame | ContextType::LazyGroupBy => {
@@ -817,8 +1076,47 @@ pub fn dispatch_add_null_row(handle: PolarsHandle) -> FfiResult {
     }
 }
 
+/// SQL context operation - registers each table's plan under its name and runs the query
+/// The tables stay lazy, so the optimizer sees the whole plan across them
+pub fn dispatch_sql_context(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    if context.operation_args == 0 {
+        return FfiResult::error(ERROR_NULL_ARGS, "SqlContextArgs cannot be null");
+    }
+
+    let args = unsafe { &*(context.operation_args as *const SqlContextArgs) };
+
+    let sql = match unsafe { args.sql.as_str() } {
+        Ok(s) => s,
+        Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in SQL query"),
+    };
+
+    let tables = if args.tables.is_null() || args.count == 0 {
+        &[][..]
+    } else {
+        unsafe { std::slice::from_raw_parts(args.tables, args.count) }
+    };
+
+    let mut sql_ctx = SQLContext::new();
+    for table in tables {
+        let name = match unsafe { table.name.as_str() } {
+            Ok(s) => s,
+            Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in table name"),
+        };
+        match execute_subplan(&table.plan) {
+            Ok(lazy_frame) => sql_ctx.register(name, lazy_frame),
+            Err(e) => return e,
+        }
+    }
+
+    match sql_ctx.execute(sql) {
+        Ok(lazy_frame) => FfiResult::success_lazy(lazy_frame),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
 /// Execute SQL query on a DataFrame
-/// Registers the DataFrame as "df" table and executes the SQL query
+/// Registers the DataFrame as "df" table and executes the SQL query, after binding any
+/// QueryParams values to its placeholders
 pub fn dispatch_query(handle: PolarsHandle, ctx: &ExecutionContext) -> FfiResult {
     if ctx.operation_args == 0 {
         return FfiResult::error(ERROR_NULL_ARGS, "QueryArgs cannot be null");
@@ -831,6 +1129,24 @@ pub fn dispatch_query(handle: PolarsHandle, ctx: &ExecutionContext) -> FfiResult
         Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in SQL query"),
     };
 
+    // Placeholders are bound in the parsed statement, which runs without going back to text
+    let run = |mut sql_ctx: SQLContext| {
+        let result = if args.param_count == 0 {
+            sql_ctx.execute(sql)
+        } else {
+            let params = unsafe { std::slice::from_raw_parts(args.params, args.param_count) };
+            match bind_query_params(sql, params) {
+                Ok(statement) => sql_ctx.execute_statement(&statement),
+                Err(e) => return e,
+            }
+        };
+        match result {
+            // Return as LazyFrame for further operations
+            Ok(lazy_frame) => FfiResult::success_lazy(lazy_frame),
+            Err(e) => FfiResult::polars_error(&e),
+        }
+    };
+
     match handle.get_context_type() {
         Some(ContextType::DataFrame) => {
             let df = unsafe { &*(handle.handle as *const DataFrame) };
@@ -838,15 +1154,7 @@ pub fn dispatch_query(handle: PolarsHandle, ctx: &ExecutionContext) -> FfiResult
             // Create SQL context and register the DataFrame as "df"
             let mut sql_ctx = SQLContext::new();
             sql_ctx.register("df", df.clone().lazy());
-
-            // Execute the SQL query
-            match sql_ctx.execute(sql) {
-                Ok(lazy_frame) => {
-                    // Return as LazyFrame for further operations
-                    FfiResult::success_lazy(lazy_frame)
-                }
-                Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
-            }
+            run(sql_ctx)
         }
         Some(ContextType::LazyFrame) => {
             let lazy_frame = unsafe { &*(handle.handle as *const LazyFrame) };
@@ -854,12 +1162,7 @@ pub fn dispatch_query(handle: PolarsHandle, ctx: &ExecutionContext) -> FfiResult
             // Create SQL context and register the LazyFrame as "df"
             let mut sql_ctx = SQLContext::new();
             sql_ctx.register("df", lazy_frame.clone());
-
-            // Execute the SQL query
-            match sql_ctx.execute(sql) {
-                Ok(result_lazy_frame) => FfiResult::success_lazy(result_lazy_frame),
-                Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
-            }
+            run(sql_ctx)
         }
         Some(ContextType::LazyGroupBy) => FfiResult::error(
             ERROR_POLARS_OPERATION,
@@ -971,6 +1274,6 @@ pub fn dispatch_from_memory(context: &ExecutionContext) -> FfiResult {
     // Create DataFrame from Columns
     match DataFrame::new(columns_vec) {
         Ok(df) => FfiResult::success(df),
-        Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
+        Err(e) => FfiResult::polars_error(&e),
     }
 }
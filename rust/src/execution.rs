@@ -1,4 +1,11 @@
-use crate::{ContextType, FfiResult, OpCode, Operation, PolarsHandle, ERROR_POLARS_OPERATION};
+use crate::{
+    cancelled_error, catch_panic, is_cancelled, write_error, ContextType, FfiResult, OpCode, Operation, PolarsHandle, ERROR_PANIC,
+    ERROR_POLARS_OPERATION, ERROR_COLUMN_NOT_FOUND, ERROR_SCHEMA_MISMATCH, ERROR_INVALID_OPERATION, polars_error_code,
+};
+use std::cell::Cell;
+use std::sync::Arc;
+use std::ffi::CString;
+use std::os::raw::{c_char, c_int};
 use polars::prelude::*;
 
 /// ExecutionContext holds the expression stack and operation arguments
@@ -111,6 +118,35 @@ fn dispatch_expression_operation(opcode: OpCode, ctx: &ExecutionContext) -> FfiR
         OpCode::ExprOtherwise => expr_otherwise(ctx),
         // Cast operations
         OpCode::ExprCast => expr_cast(ctx),
+        // Rolling window operations
+        OpCode::ExprRollingMean => expr_rolling_mean(ctx),
+        OpCode::ExprRollingSum => expr_rolling_sum(ctx),
+        OpCode::ExprRollingMin => expr_rolling_min(ctx),
+        OpCode::ExprRollingMax => expr_rolling_max(ctx),
+        OpCode::ExprRollingStd => expr_rolling_std(ctx),
+        OpCode::ExprRollingMedian => expr_rolling_median(ctx),
+        OpCode::ExprRollingQuantile => expr_rolling_quantile(ctx),
+        // Cumulative and difference operations
+        OpCode::ExprCumSum => expr_cum_sum(ctx),
+        OpCode::ExprCumMin => expr_cum_min(ctx),
+        OpCode::ExprCumMax => expr_cum_max(ctx),
+        OpCode::ExprCumProd => expr_cum_prod(ctx),
+        OpCode::ExprCumCount => expr_cum_count(ctx),
+        OpCode::ExprDiff => expr_diff(ctx),
+        OpCode::ExprPctChange => expr_pct_change(ctx),
+        // Extended aggregations
+        OpCode::ExprQuantile => expr_quantile(ctx),
+        OpCode::ExprMode => expr_mode(ctx),
+        OpCode::ExprApproxNUnique => expr_approx_n_unique(ctx),
+        OpCode::ExprArgMin => expr_arg_min(ctx),
+        OpCode::ExprArgMax => expr_arg_max(ctx),
+        OpCode::ExprSkew => expr_skew(ctx),
+        OpCode::ExprKurtosis => expr_kurtosis(ctx),
+        OpCode::ExprProduct => expr_product(ctx),
+        OpCode::ExprImplode => expr_implode(ctx),
+        OpCode::ExprAggGroups => expr_agg_groups(ctx),
+        // User-defined functions
+        OpCode::ExprMapBatches => crate::udf::expr_map_batches(ctx),
         _ => FfiResult::error(ERROR_POLARS_OPERATION, "Unsupported expression operation"),
     }
 }
@@ -128,13 +164,21 @@ fn dispatch_dataframe_operation(
         OpCode::NewEmpty => (dispatch_new_empty(), ContextType::DataFrame),
         OpCode::ReadCsv => (dispatch_read_csv(handle, context), ContextType::LazyFrame),
         OpCode::ReadParquet => (dispatch_read_parquet(handle, context), ContextType::LazyFrame),
+        OpCode::ReadNdjson => (dispatch_read_ndjson(handle, context), ContextType::LazyFrame),
+        OpCode::ReadJson => (dispatch_read_json(handle, context), ContextType::DataFrame),
+        OpCode::ReadIpc => (dispatch_read_ipc(handle, context), ContextType::DataFrame),
+        OpCode::ScanIpc => (dispatch_scan_ipc(handle, context), ContextType::LazyFrame),
+        OpCode::ReadIpcStream => (dispatch_read_ipc_stream(handle, context), ContextType::DataFrame),
+        OpCode::ReadCsvBuffer => (dispatch_read_csv_buffer(handle, context), ContextType::DataFrame),
+        OpCode::ReadParquetBuffer => (dispatch_read_parquet_buffer(handle, context), ContextType::DataFrame),
+        OpCode::ReadNdjsonBuffer => (dispatch_read_ndjson_buffer(handle, context), ContextType::DataFrame),
         OpCode::Select => (dispatch_select(handle, context), ContextType::LazyFrame),
         OpCode::SelectExpr => (
             dispatch_select_expr(handle, context),
             ContextType::LazyFrame,
         ),
         OpCode::Count => (dispatch_count(handle), ContextType::LazyFrame),
-        OpCode::Concat => (dispatch_concat(handle, context), ContextType::DataFrame),
+        OpCode::Concat => (dispatch_concat(handle, context), ContextType::LazyFrame),
         OpCode::WithColumn => (
             dispatch_with_column(handle, context),
             ContextType::LazyFrame,
@@ -156,14 +200,15 @@ fn dispatch_dataframe_operation(
             (dispatch_limit(handle, context), input_context)
         }
         OpCode::AddNullRow => (dispatch_add_null_row(handle), ContextType::DataFrame),
+        OpCode::RaisePanic => (dispatch_raise_panic(), ContextType::DataFrame),
         OpCode::Collect => (dispatch_collect(handle), ContextType::DataFrame),
         OpCode::Query => (dispatch_query(handle, context), ContextType::LazyFrame),
-        OpCode::Join => {
-            // Join preserves the input context type (DataFrame->DataFrame, LazyFrame->LazyFrame)
-            let input_context = handle.get_context_type().unwrap_or(ContextType::DataFrame);
-            (dispatch_join(handle, context), input_context)
-        }
+        OpCode::SqlContext => (dispatch_sql_context(handle, context), ContextType::LazyFrame),
+        OpCode::DeserializePlan => (crate::plan::dispatch_deserialize_plan(handle, context), ContextType::LazyFrame),
+        OpCode::Join => (dispatch_join(handle, context), ContextType::LazyFrame),
         OpCode::FromMemory => (dispatch_from_memory(context), ContextType::DataFrame),
+        OpCode::Describe => (dispatch_describe(handle, context), ContextType::DataFrame),
+        OpCode::NullCount => (dispatch_null_count(handle), ContextType::LazyFrame),
         _ => (
             FfiResult::error(ERROR_POLARS_OPERATION, "Unsupported DataFrame operation"),
             handle.get_context_type().unwrap_or(ContextType::DataFrame),
@@ -171,12 +216,174 @@ fn dispatch_dataframe_operation(
     }
 }
 
+/// A plan embedded in another operation's arguments, e.g. the right side of a join
+/// `handle` is the plan's starting point (0 if its first operation creates the data)
+/// and `operations` are its pending, not yet executed operations
+#[repr(C)]
+pub struct SubPlan {
+    pub handle: PolarsHandle,
+    pub operations: *const Operation,
+    pub count: usize,
+}
+
+/// Free a handle produced while executing a sub-plan
+fn release_handle(handle: PolarsHandle) {
+    if handle.handle == 0 {
+        return;
+    }
+    unsafe {
+        match handle.get_context_type() {
+            // DataFrame handles are Arc pointers that Go may also hold a reference to
+            Some(ContextType::DataFrame) => {
+                Arc::decrement_strong_count(handle.handle as *const DataFrame)
+            }
+            Some(ContextType::LazyFrame) => drop(Box::from_raw(handle.handle as *mut LazyFrame)),
+            Some(ContextType::LazyGroupBy) => {
+                drop(Box::from_raw(handle.handle as *mut LazyGroupBy))
+            }
+            None => {}
+        }
+    }
+}
+
+/// Resolve a sub-plan to a LazyFrame without collecting it, so the optimizer
+/// sees the combined plan (predicate/projection pushdown across joins and unions)
+pub fn execute_subplan(plan: &SubPlan) -> std::result::Result<LazyFrame, FfiResult> {
+    let (handle, owned) = if plan.count == 0 || plan.operations.is_null() {
+        (plan.handle, false)
+    } else {
+        let result = execute_operations(plan.handle, plan.operations, plan.count);
+        if result.error_code != 0 {
+            return Err(result);
+        }
+        // Handles created by the sub-plan are owned here; the starting handle belongs to Go
+        let owned = result.polars_handle.handle != plan.handle.handle;
+        (result.polars_handle, owned)
+    };
+
+    if handle.handle == 0 {
+        return Err(FfiResult::error(
+            crate::ERROR_NULL_HANDLE,
+            "Sub-plan has no data: DataFrame was never read or was released",
+        ));
+    }
+
+    let lazy_frame = match handle.get_context_type() {
+        Some(ContextType::DataFrame) => {
+            let df = unsafe { &*(handle.handle as *const DataFrame) };
+            Ok(df.clone().lazy())
+        }
+        Some(ContextType::LazyFrame) => {
+            let lazy_frame = unsafe { &*(handle.handle as *const LazyFrame) };
+            Ok(lazy_frame.clone())
+        }
+        Some(ContextType::LazyGroupBy) => Err(FfiResult::error(
+            ERROR_POLARS_OPERATION,
+            "Sub-plan ends in grouped data. Call agg() first to resolve grouping.",
+        )),
+        None => Err(FfiResult::error(ERROR_POLARS_OPERATION, "Invalid sub-plan context type")),
+    };
+
+    if owned {
+        release_handle(handle);
+    }
+    lazy_frame
+}
+
+/// Render the query plan of a sub-plan without collecting it
+/// optimized selects the plan after predicate/projection pushdown and other optimizations
+/// Returns the plan text (free with free_string), or null with error_code and error_message set
+#[no_mangle]
+pub extern "C" fn explain_plan(
+    plan: SubPlan,
+    optimized: bool,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut c_char {
+    let fail = |code: c_int, message: &str| {
+        write_error(error_code, error_message, code, message);
+        std::ptr::null_mut()
+    };
+
+    catch_panic(
+        || {
+            let lazy_frame = match execute_subplan(&plan) {
+                Ok(lf) => lf,
+                Err(result) => {
+                    let (code, message) = take_ffi_error(result);
+                    return fail(code, &message);
+                }
+            };
+
+            match lazy_frame.explain(optimized) {
+                Ok(text) => match CString::new(text) {
+                    Ok(c_string) => c_string.into_raw(),
+                    Err(_) => fail(ERROR_POLARS_OPERATION, "Plan contains interior NUL byte"),
+                },
+                Err(e) => fail(polars_error_code(&e), &e.to_string()),
+            }
+        },
+        |message| fail(ERROR_PANIC, &message),
+    )
+}
+
+/// Take the error code and message out of a failed FfiResult, freeing the message
+pub(crate) fn take_ffi_error(result: FfiResult) -> (c_int, String) {
+    let message = if result.error_message.is_null() {
+        "Failed to execute plan".to_string()
+    } else {
+        unsafe { CString::from_raw(result.error_message) }.to_string_lossy().into_owned()
+    };
+    (result.error_code, message)
+}
+
 /// Main execution function - processes a chain of operations with context tracking
+/// A panic is reported as ERROR_PANIC at the operation that raised it; handles created
+/// by earlier operations in the chain are leaked rather than risk freeing them twice
 #[no_mangle]
 pub extern "C" fn execute_operations(
     polars_handle: PolarsHandle,
     operations_ptr: *const Operation,
     count: usize,
+) -> FfiResult {
+    let frame = Cell::new(0);
+    catch_panic(
+        || run_operations(polars_handle, operations_ptr, count, &frame),
+        |message| FfiResult {
+            error_frame: frame.get(),
+            ..FfiResult::error(ERROR_PANIC, &message)
+        },
+    )
+}
+
+/// Find the operation whose LazyFrame first fails schema resolution with error_code
+/// A missing column or mismatched type in a Filter only surfaces when the plan is collected;
+/// resolving the schema of the intermediate plans, which stay alive for the whole run,
+/// narrows it down without executing anything again. Plans stay broken once broken, so a
+/// binary search over the operations is enough.
+fn locate_plan_error(lazy_frames: &[(usize, usize)], error_code: c_int) -> Option<usize> {
+    if ![ERROR_COLUMN_NOT_FOUND, ERROR_SCHEMA_MISMATCH, ERROR_INVALID_OPERATION].contains(&error_code) {
+        return None;
+    }
+
+    let schema_error = |handle: usize| {
+        let lazy_frame = unsafe { &*(handle as *const LazyFrame) };
+        lazy_frame.clone().collect_schema().err()
+    };
+    let first_broken = lazy_frames.partition_point(|&(_, handle)| schema_error(handle).is_none());
+    let &(frame, handle) = lazy_frames.get(first_broken)?;
+    match schema_error(handle) {
+        Some(e) if polars_error_code(&e) == error_code => Some(frame),
+        _ => None,
+    }
+}
+
+/// Body of execute_operations; frame tracks the operation being dispatched
+fn run_operations(
+    polars_handle: PolarsHandle,
+    operations_ptr: *const Operation,
+    count: usize,
+    frame: &Cell<usize>,
 ) -> FfiResult {
     if operations_ptr.is_null() || count == 0 {
         return FfiResult::error(ERROR_POLARS_OPERATION, "Operations cannot be null or empty");
@@ -188,8 +395,13 @@ pub extern "C" fn execute_operations(
         .get_context_type()
         .unwrap_or(ContextType::DataFrame); // Use the actual context from the handle
     let mut expr_stack = Vec::new(); // Expression stack for building expressions
+    let mut lazy_frames = Vec::new(); // (frame, handle) of each LazyFrame produced, for locate_plan_error
 
     for (frame_idx, op) in operations.iter().enumerate() {
+        frame.set(frame_idx);
+        if is_cancelled() {
+            return FfiResult { error_frame: frame_idx, ..cancelled_error() };
+        }
 
         let opcode = match op.get_opcode() {
             Some(opcode) => opcode,
@@ -228,18 +440,26 @@ pub extern "C" fn execute_operations(
         };
 
         if result.error_code != 0 {
+            // Lazy plans are only resolved at Collect; blame the operation that broke the plan
+            let error_frame = match opcode {
+                OpCode::Collect => locate_plan_error(&lazy_frames, result.error_code).unwrap_or(frame_idx),
+                _ => frame_idx,
+            };
             // Return error with frame information
             return FfiResult {
                 polars_handle: PolarsHandle::new(0, ContextType::DataFrame), // Error case
                 error_code: result.error_code,
                 error_message: result.error_message,
-                error_frame: frame_idx,
+                error_frame,
             };
         }
 
         // Only update handle for DataFrame operations, not expression operations
         if opcode.is_dataframe_op() {
             current_handle = result.polars_handle.handle;
+            if new_context_type == ContextType::LazyFrame {
+                lazy_frames.push((frame_idx, current_handle));
+            }
         }
         current_context_type = new_context_type;
     }
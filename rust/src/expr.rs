@@ -1,7 +1,8 @@
-use crate::{ExecutionContext, FfiResult, ERROR_INVALID_UTF8, ERROR_POLARS_OPERATION};
+use crate::{ExecutionContext, FfiResult, ERROR_INVALID_UTF8, ERROR_POLARS_OPERATION, polars_error_code};
 use crate::types::{
-    decode_data_type, AggregationArgs, AliasArgs, CastArgs, ColumnArgs, CountArgs, HeadTailArgs,
-    LiteralArgs, PadArgs, ReplaceArgs, SliceArgs, SplitArgs, StringArgs,
+    decode_data_type, AggregationArgs, AliasArgs, CastArgs, ColumnArgs, CountArgs, CumArgs,
+    DiffArgs, HeadTailArgs, LiteralArgs, MomentArgs, PadArgs, QuantileArgs, ReplaceArgs,
+    RollingArgs, SliceArgs, SplitArgs, StringArgs,
 };
 use polars::prelude::*;
 
@@ -58,7 +59,7 @@ pub fn expr_cast(ctx: &ExecutionContext) -> FfiResult {
 }
 
 
-fn unary_expr_op<F>(ctx: &ExecutionContext, op_name: &str, op: F) -> FfiResult
+pub(crate) fn unary_expr_op<F>(ctx: &ExecutionContext, op_name: &str, op: F) -> FfiResult
 where
     F: FnOnce(Expr) -> Expr,
 {
@@ -236,6 +237,77 @@ pub fn expr_nunique(ctx: &ExecutionContext) -> FfiResult {
     unary_expr_op(ctx, "nunique", |expr| expr.n_unique())
 }
 
+/// Quantile aggregation - applies quantile with the requested interpolation method
+pub fn expr_quantile(ctx: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(ctx.operation_args as *const QuantileArgs) };
+
+    let method = match args.interpolation {
+        0 => QuantileMethod::Nearest,
+        1 => QuantileMethod::Lower,
+        2 => QuantileMethod::Higher,
+        3 => QuantileMethod::Midpoint,
+        4 => QuantileMethod::Linear,
+        other => {
+            return FfiResult::error(
+                ERROR_POLARS_OPERATION,
+                &format!("Unknown quantile interpolation: {}", other),
+            )
+        }
+    };
+
+    let quantile = args.quantile;
+    unary_expr_op(ctx, "quantile", |expr| expr.quantile(lit(quantile), method))
+}
+
+/// Mode - most frequent value(s) of the top expression on the stack
+pub fn expr_mode(ctx: &ExecutionContext) -> FfiResult {
+    unary_expr_op(ctx, "mode", |expr| expr.mode())
+}
+
+/// Approximate unique count (HyperLogLog) of the top expression on the stack
+pub fn expr_approx_n_unique(ctx: &ExecutionContext) -> FfiResult {
+    unary_expr_op(ctx, "approx_n_unique", |expr| expr.approx_n_unique())
+}
+
+/// Index of the minimum value
+pub fn expr_arg_min(ctx: &ExecutionContext) -> FfiResult {
+    unary_expr_op(ctx, "arg_min", |expr| expr.arg_min())
+}
+
+/// Index of the maximum value
+pub fn expr_arg_max(ctx: &ExecutionContext) -> FfiResult {
+    unary_expr_op(ctx, "arg_max", |expr| expr.arg_max())
+}
+
+/// Skewness aggregation - uses MomentArgs.bias
+pub fn expr_skew(ctx: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(ctx.operation_args as *const MomentArgs) };
+    let bias = args.bias;
+    unary_expr_op(ctx, "skew", |expr| expr.skew(bias))
+}
+
+/// Kurtosis aggregation - uses MomentArgs.fisher and MomentArgs.bias
+pub fn expr_kurtosis(ctx: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(ctx.operation_args as *const MomentArgs) };
+    let (fisher, bias) = (args.fisher, args.bias);
+    unary_expr_op(ctx, "kurtosis", |expr| expr.kurtosis(fisher, bias))
+}
+
+/// Product aggregation - multiplies all values of the top expression on the stack
+pub fn expr_product(ctx: &ExecutionContext) -> FfiResult {
+    unary_expr_op(ctx, "product", |expr| expr.product())
+}
+
+/// Implode - aggregates all values into a single list
+pub fn expr_implode(ctx: &ExecutionContext) -> FfiResult {
+    unary_expr_op(ctx, "implode", |expr| expr.implode())
+}
+
+/// AggGroups - row indices belonging to each group
+pub fn expr_agg_groups(ctx: &ExecutionContext) -> FfiResult {
+    unary_expr_op(ctx, "agg_groups", |expr| expr.agg_groups())
+}
+
 pub fn expr_count(ctx: &ExecutionContext) -> FfiResult {
     let expr_stack = unsafe { &mut *ctx.expr_stack };
     let args = unsafe { &*(ctx.operation_args as *const CountArgs) };
@@ -678,7 +750,7 @@ pub fn expr_sql(ctx: &ExecutionContext) -> FfiResult {
             FfiResult::success_no_handle()
         }
         Err(e) => FfiResult::error(
-            ERROR_POLARS_OPERATION,
+            polars_error_code(&e),
             &format!("SQL expression parsing failed: {}", e),
         ),
     }
@@ -818,6 +890,188 @@ pub fn expr_lead(ctx: &ExecutionContext) -> FfiResult {
     FfiResult::success_no_handle()
 }
 
+// Rolling window operations
+
+/// Rolling aggregation applied by rolling_expr_op
+#[derive(Clone, Copy)]
+enum RollingKind {
+    Mean,
+    Sum,
+    Min,
+    Max,
+    Std,
+    Median,
+    Quantile,
+}
+
+/// Helper for rolling aggregations - builds either a fixed-size window (row count)
+/// or a duration-based window over an index column, depending on RollingArgs.by
+fn rolling_expr_op(ctx: &ExecutionContext, op_name: &str, kind: RollingKind) -> FfiResult {
+    let expr_stack = unsafe { &mut *ctx.expr_stack };
+    let args = unsafe { &*(ctx.operation_args as *const RollingArgs) };
+
+    if expr_stack.is_empty() {
+        return FfiResult::error(
+            ERROR_POLARS_OPERATION,
+            &format!("{} requires 1 expression on stack", op_name),
+        );
+    }
+
+    let by = match unsafe { args.by.as_str() } {
+        Ok(s) => s,
+        Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in rolling index column"),
+    };
+
+    let fn_params = match kind {
+        RollingKind::Std => Some(RollingFnParams::Var(RollingVarParams { ddof: args.ddof })),
+        _ => None,
+    };
+
+    if by.is_empty() {
+        // Fixed window measured in rows
+        let options = RollingOptionsFixedWindow {
+            window_size: args.window_size,
+            min_periods: args.min_periods,
+            center: args.center,
+            fn_params,
+            ..Default::default()
+        };
+
+        let expr = expr_stack.pop().unwrap();
+        expr_stack.push(match kind {
+            RollingKind::Mean => expr.rolling_mean(options),
+            RollingKind::Sum => expr.rolling_sum(options),
+            RollingKind::Min => expr.rolling_min(options),
+            RollingKind::Max => expr.rolling_max(options),
+            RollingKind::Std => expr.rolling_std(options),
+            RollingKind::Median => expr.rolling_median(options),
+            RollingKind::Quantile => {
+                expr.rolling_quantile(QuantileMethod::Linear, args.quantile, options)
+            }
+        });
+    } else {
+        // Duration window over a temporal (or integer) index column
+        let duration_str = match unsafe { args.duration.as_str() } {
+            Ok(s) => s,
+            Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in rolling duration"),
+        };
+
+        let window_size = match Duration::try_parse(duration_str) {
+            Ok(d) => d,
+            Err(e) => {
+                return FfiResult::error(
+                    ERROR_POLARS_OPERATION,
+                    &format!("{}: invalid window duration {:?}: {}", op_name, duration_str, e),
+                )
+            }
+        };
+
+        let options = RollingOptionsDynamicWindow {
+            window_size,
+            min_periods: args.min_periods,
+            closed_window: ClosedWindow::Right,
+            fn_params,
+        };
+
+        let index = col(by);
+        let expr = expr_stack.pop().unwrap();
+        expr_stack.push(match kind {
+            RollingKind::Mean => expr.rolling_mean_by(index, options),
+            RollingKind::Sum => expr.rolling_sum_by(index, options),
+            RollingKind::Min => expr.rolling_min_by(index, options),
+            RollingKind::Max => expr.rolling_max_by(index, options),
+            RollingKind::Std => expr.rolling_std_by(index, options),
+            RollingKind::Median => expr.rolling_median_by(index, options),
+            RollingKind::Quantile => {
+                expr.rolling_quantile_by(index, QuantileMethod::Linear, args.quantile, options)
+            }
+        });
+    }
+
+    FfiResult::success_no_handle()
+}
+
+/// Rolling mean over a fixed or duration-based window
+pub fn expr_rolling_mean(ctx: &ExecutionContext) -> FfiResult {
+    rolling_expr_op(ctx, "rolling_mean", RollingKind::Mean)
+}
+
+/// Rolling sum over a fixed or duration-based window
+pub fn expr_rolling_sum(ctx: &ExecutionContext) -> FfiResult {
+    rolling_expr_op(ctx, "rolling_sum", RollingKind::Sum)
+}
+
+/// Rolling min over a fixed or duration-based window
+pub fn expr_rolling_min(ctx: &ExecutionContext) -> FfiResult {
+    rolling_expr_op(ctx, "rolling_min", RollingKind::Min)
+}
+
+/// Rolling max over a fixed or duration-based window
+pub fn expr_rolling_max(ctx: &ExecutionContext) -> FfiResult {
+    rolling_expr_op(ctx, "rolling_max", RollingKind::Max)
+}
+
+/// Rolling standard deviation over a fixed or duration-based window
+pub fn expr_rolling_std(ctx: &ExecutionContext) -> FfiResult {
+    rolling_expr_op(ctx, "rolling_std", RollingKind::Std)
+}
+
+/// Rolling median over a fixed or duration-based window
+pub fn expr_rolling_median(ctx: &ExecutionContext) -> FfiResult {
+    rolling_expr_op(ctx, "rolling_median", RollingKind::Median)
+}
+
+/// Rolling quantile (linear interpolation) over a fixed or duration-based window
+pub fn expr_rolling_quantile(ctx: &ExecutionContext) -> FfiResult {
+    rolling_expr_op(ctx, "rolling_quantile", RollingKind::Quantile)
+}
+
+// Cumulative and difference operations
+
+/// Helper for cumulative operations that take CumArgs
+fn cum_expr_op<F>(ctx: &ExecutionContext, op_name: &str, op: F) -> FfiResult
+where
+    F: FnOnce(Expr, bool) -> Expr,
+{
+    let args = unsafe { &*(ctx.operation_args as *const CumArgs) };
+    let reverse = args.reverse;
+    unary_expr_op(ctx, op_name, |expr| op(expr, reverse))
+}
+
+pub fn expr_cum_sum(ctx: &ExecutionContext) -> FfiResult {
+    cum_expr_op(ctx, "cum_sum", |expr, reverse| expr.cum_sum(reverse))
+}
+
+pub fn expr_cum_min(ctx: &ExecutionContext) -> FfiResult {
+    cum_expr_op(ctx, "cum_min", |expr, reverse| expr.cum_min(reverse))
+}
+
+pub fn expr_cum_max(ctx: &ExecutionContext) -> FfiResult {
+    cum_expr_op(ctx, "cum_max", |expr, reverse| expr.cum_max(reverse))
+}
+
+pub fn expr_cum_prod(ctx: &ExecutionContext) -> FfiResult {
+    cum_expr_op(ctx, "cum_prod", |expr, reverse| expr.cum_prod(reverse))
+}
+
+pub fn expr_cum_count(ctx: &ExecutionContext) -> FfiResult {
+    cum_expr_op(ctx, "cum_count", |expr, reverse| expr.cum_count(reverse))
+}
+
+/// Diff - difference between each value and the value n rows earlier
+pub fn expr_diff(ctx: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(ctx.operation_args as *const DiffArgs) };
+    let n = args.n;
+    unary_expr_op(ctx, "diff", |expr| expr.diff(lit(n), NullBehavior::Ignore))
+}
+
+/// Percentage change between each value and the value n rows earlier
+pub fn expr_pct_change(ctx: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(ctx.operation_args as *const DiffArgs) };
+    let n = args.n;
+    unary_expr_op(ctx, "pct_change", |expr| expr.pct_change(lit(n)))
+}
+
 // Conditional expression operations
 
 /// When operation - starts a conditional chain
@@ -1,8 +1,21 @@
 use crate::{
-    ExecutionContext, FfiResult, PolarsHandle, RawStr, 
-    ERROR_INVALID_UTF8, ERROR_POLARS_OPERATION,
+    decode_data_type, execute_expr_ops, write_error_message, Operation, ExecutionContext, FfiResult, PolarsHandle, RawStr,
+    ERROR_INVALID_UTF8, ERROR_NULL_ARGS, ERROR_NULL_HANDLE, ERROR_POLARS_OPERATION,
+    ERROR_INVALID_OPERATION, ERROR_IO, ERROR_PANIC, ERROR_CANCELLED, catch_panic, polars_error_code, write_error,
+    is_cancelled, with_cancel_token, CancellableWriter,
 };
-use polars::prelude::{LazyFrame, LazyCsvReader, ScanArgsParquet, LazyFileListReader, PlPath};
+use polars::prelude::{LazyFrame, LazyCsvReader, ScanArgsParquet, LazyFileListReader, PlPath,
+    CsvEncoding, Field, IdxSize, NullValues, PlSmallStr, RowIndex, Schema,
+    LazyJsonLineReader, JsonReader, JsonFormat, SerReader, SerWriter, DataFrame,
+    IpcReader, IpcWriter, IpcCompression, IpcStreamReader, IpcStreamWriter, ScanArgsIpc,
+    CsvReadOptions, CsvParseOptions, CsvWriter, ParquetReader, ParquetWriter, AnyValue, PolarsResult};
+use polars::prelude::cloud::{AmazonS3ConfigKey, CloudOptions};
+use std::fs::{File, OpenOptions};
+use std::io::Cursor;
+use std::os::raw::{c_char, c_int};
+use std::path::{Path, PathBuf};
+use std::num::NonZeroUsize;
+use std::sync::Arc;
 
 /// Helper function to convert RawStr array to Vec<String>
 unsafe fn raw_str_array_to_vec(
@@ -26,12 +39,89 @@ unsafe fn raw_str_array_to_vec(
     Ok(result)
 }
 
+/// Column data type override for CSV reading
+#[repr(C)]
+pub struct SchemaOverride {
+    pub name: RawStr, // Column name
+    pub dtype: u32,   // Bit-packed DataType
+}
+
+/// Object store settings for s3:// paths
+#[repr(C)]
+pub struct CloudArgs {
+    pub endpoint: RawStr,          // Custom endpoint, e.g. a local MinIO (empty = AWS)
+    pub region: RawStr,            // Region (empty = from environment)
+    pub access_key_id: RawStr,     // Static credentials (empty = default credential chain)
+    pub secret_access_key: RawStr,
+    pub session_token: RawStr,     // Optional session token for temporary credentials
+    pub allow_http: bool,          // Permit plain-HTTP endpoints
+}
+
+/// Convert optional CloudArgs into Polars cloud options (null = local or default settings)
+fn cloud_options(args: *const CloudArgs) -> Result<Option<CloudOptions>, FfiResult> {
+    if args.is_null() {
+        return Ok(None);
+    }
+    let args = unsafe { &*args };
+
+    let settings = [
+        ("aws_endpoint", &args.endpoint),
+        ("aws_region", &args.region),
+        ("aws_access_key_id", &args.access_key_id),
+        ("aws_secret_access_key", &args.secret_access_key),
+        ("aws_session_token", &args.session_token),
+    ];
+    let mut config = Vec::with_capacity(settings.len() + 1);
+    for (key, value) in settings {
+        match unsafe { value.as_str() } {
+            Ok("") => {}
+            Ok(v) => config.push((key, v.to_string())),
+            Err(_) => return Err(FfiResult::error(ERROR_INVALID_UTF8, &format!("Invalid UTF-8 in {}", key))),
+        }
+    }
+    if args.allow_http {
+        config.push(("allow_http", "true".to_string()));
+    }
+
+    let mut aws_config = Vec::with_capacity(config.len());
+    for (key, value) in config {
+        match key.parse::<AmazonS3ConfigKey>() {
+            Ok(k) => aws_config.push((k, value)),
+            Err(e) => return Err(FfiResult::error(ERROR_INVALID_OPERATION, &e.to_string())),
+        }
+    }
+
+    Ok(Some(CloudOptions::default().with_aws(aws_config)))
+}
+
+/// CSV text encoding
+pub const CSV_ENCODING_UTF8: u8 = 0;
+pub const CSV_ENCODING_UTF8_LOSSY: u8 = 1;
+
 /// Arguments for reading CSV files
 #[repr(C)]
 pub struct ReadCsvArgs {
-    pub path: RawStr,     // File path using zero-copy RawStr
-    pub has_header: bool, // Whether CSV has header row
-    pub with_glob: bool,  // Whether to expand glob patterns
+    pub path: RawStr,                             // File path using zero-copy RawStr
+    pub has_header: bool,                         // Whether CSV has header row
+    pub with_glob: bool,                          // Whether to expand glob patterns
+    pub separator: u8,                            // Field separator (0 = ',')
+    pub quote_char: u8,                           // Quote character (0 = '"')
+    pub comment_prefix: RawStr,                   // Lines starting with this are skipped (empty = none)
+    pub skip_rows: usize,                         // Rows to skip before the header
+    pub n_rows: usize,                            // Number of rows to read (0 for all)
+    pub schema_overrides: *const SchemaOverride,  // Column dtype overrides (null for none)
+    pub schema_override_count: usize,             // Number of overrides
+    pub null_values: *const RawStr,               // Strings read as null (null for none)
+    pub null_value_count: usize,                  // Number of null value strings
+    pub infer_schema_length: i64,                 // Rows used for inference (0 = default, <0 = all)
+    pub try_parse_dates: bool,                    // Parse date/datetime-looking strings
+    pub ignore_errors: bool,                      // Turn unparsable values into nulls
+    pub encoding: u8,                             // CSV_ENCODING_* constant
+    pub low_memory: bool,                         // Reduce memory usage at the cost of speed
+    pub row_index_name: RawStr,                   // Name of row index column (empty = none)
+    pub row_index_offset: u32,                    // First value of the row index
+    pub cloud: *const CloudArgs,                  // Object store settings (null = none)
+    pub include_file_paths: RawStr,               // Source file path column name (empty = none)
 }
 
 /// Arguments for reading Parquet files
@@ -42,7 +132,114 @@ pub struct ReadParquetArgs {
     pub column_count: usize,        // Number of columns to select
     pub n_rows: usize,              // Number of rows to read (0 for all)
     pub parallel: bool,             // Whether to read in parallel
-    pub with_glob: bool,            // Whether to expand glob patterns
+    pub with_glob: bool,            // Ignored; globs are always expanded
+    pub hive_partitioning: bool,    // Parse key=value directories into columns (false = Polars default)
+    pub hive_schema: *const SchemaOverride, // Partition column dtypes (null = inferred)
+    pub hive_schema_count: usize,   // Number of partition column dtypes
+    pub try_parse_hive_dates: bool, // Parse date-looking partition values as dates
+    pub cloud: *const CloudArgs,    // Object store settings (null = none)
+    pub row_index_name: RawStr,     // Name of row index column (empty = none)
+    pub row_index_offset: u32,      // First value of the row index
+    pub include_file_paths: RawStr, // Source file path column name (empty = none)
+    pub low_memory: bool,           // Reduce memory usage at the cost of speed
+    pub use_statistics: bool,       // Skip row groups using min/max statistics
+    pub rechunk: bool,              // Rechunk the result into contiguous memory
+    pub predicate_ops: *const Operation, // Filter expression pushed into the scan (null = none)
+    pub predicate_count: usize,     // Number of predicate expression operations
+}
+
+/// Build a schema from an FFI array of column dtype overrides
+fn schema_from_overrides(
+    overrides: *const SchemaOverride,
+    count: usize,
+) -> Result<Option<Arc<Schema>>, FfiResult> {
+    if overrides.is_null() || count == 0 {
+        return Ok(None);
+    }
+
+    let overrides = unsafe { std::slice::from_raw_parts(overrides, count) };
+    let mut fields = Vec::with_capacity(overrides.len());
+    for o in overrides {
+        let name = match unsafe { o.name.as_str() } {
+            Ok(s) => s,
+            Err(_) => return Err(FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in column name")),
+        };
+        fields.push(Field::new(name.into(), decode_data_type(o.dtype)?));
+    }
+
+    Ok(Some(Arc::new(Schema::from_iter(fields))))
+}
+
+/// Decode an optional column name (empty = None)
+fn optional_name(name: &RawStr, what: &str) -> Result<Option<PlSmallStr>, FfiResult> {
+    match unsafe { name.as_str() } {
+        Ok("") => Ok(None),
+        Ok(s) => Ok(Some(PlSmallStr::from_str(s))),
+        Err(_) => Err(FfiResult::error(ERROR_INVALID_UTF8, &format!("Invalid UTF-8 in {}", what))),
+    }
+}
+
+/// Build the row index settings shared by the CSV and Parquet scans
+fn row_index(name: &RawStr, offset: u32) -> Result<Option<RowIndex>, FfiResult> {
+    Ok(optional_name(name, "row index name")?.map(|name| RowIndex {
+        name,
+        offset: offset as IdxSize,
+    }))
+}
+
+/// CSV reading options decoded from ReadCsvArgs, shared by file scans and buffer reads
+struct CsvSettings {
+    separator: u8,
+    quote_char: u8,
+    comment_prefix: Option<PlSmallStr>,
+    n_rows: Option<usize>,
+    schema_overrides: Option<Arc<Schema>>,
+    null_values: Option<NullValues>,
+    infer_schema_length: Option<usize>,
+    encoding: CsvEncoding,
+    row_index: Option<RowIndex>,
+    include_file_paths: Option<PlSmallStr>,
+}
+
+/// Decode and validate every option in ReadCsvArgs except the path
+fn csv_settings(args: &ReadCsvArgs) -> Result<CsvSettings, FfiResult> {
+    let comment_prefix = optional_name(&args.comment_prefix, "comment prefix")?;
+
+    let null_values = if args.null_values.is_null() || args.null_value_count == 0 {
+        None
+    } else {
+        match unsafe { raw_str_array_to_vec(args.null_values, args.null_value_count) } {
+            Ok(values) => Some(NullValues::AllColumns(values.into_iter().map(PlSmallStr::from).collect())),
+            Err(msg) => return Err(FfiResult::error(ERROR_INVALID_UTF8, msg)),
+        }
+    };
+
+    let infer_schema_length = match args.infer_schema_length {
+        0 => Some(100),
+        n if n < 0 => None, // Scan the whole file
+        n => Some(n as usize),
+    };
+
+    let encoding = match args.encoding {
+        CSV_ENCODING_UTF8 => CsvEncoding::Utf8,
+        CSV_ENCODING_UTF8_LOSSY => CsvEncoding::LossyUtf8,
+        other => {
+            return Err(FfiResult::error(ERROR_POLARS_OPERATION, &format!("Unknown CSV encoding: {}", other)))
+        }
+    };
+
+    Ok(CsvSettings {
+        separator: if args.separator == 0 { b',' } else { args.separator },
+        quote_char: if args.quote_char == 0 { b'"' } else { args.quote_char },
+        comment_prefix,
+        n_rows: if args.n_rows > 0 { Some(args.n_rows) } else { None },
+        schema_overrides: schema_from_overrides(args.schema_overrides, args.schema_override_count)?,
+        null_values,
+        infer_schema_length,
+        encoding,
+        row_index: row_index(&args.row_index_name, args.row_index_offset)?,
+        include_file_paths: optional_name(&args.include_file_paths, "file path column name")?,
+    })
 }
 
 /// Dispatch function for reading CSV
@@ -55,13 +252,39 @@ pub fn dispatch_read_csv(_handle: PolarsHandle, context: &ExecutionContext) -> F
         Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in path"),
     };
 
-    // Use LazyCsvReader with configurable options - return LazyFrame for lazy evaluation
-    match LazyCsvReader::new(PlPath::new(path_str))
+    let settings = match csv_settings(args) {
+        Ok(settings) => settings,
+        Err(e) => return e,
+    };
+
+    let cloud = match cloud_options(args.cloud) {
+        Ok(cloud) => cloud,
+        Err(e) => return e,
+    };
+
+    let reader = LazyCsvReader::new(PlPath::new(path_str))
         .with_has_header(args.has_header) // Configurable header detection
-        .finish()
-    {
+        .with_glob(args.with_glob)
+        .with_separator(settings.separator)
+        .with_quote_char(Some(settings.quote_char))
+        .with_comment_prefix(settings.comment_prefix)
+        .with_skip_rows(args.skip_rows)
+        .with_n_rows(settings.n_rows)
+        .with_dtype_overwrite(settings.schema_overrides)
+        .with_null_values(settings.null_values)
+        .with_infer_schema_length(settings.infer_schema_length)
+        .with_try_parse_dates(args.try_parse_dates)
+        .with_ignore_errors(args.ignore_errors)
+        .with_encoding(settings.encoding)
+        .with_low_memory(args.low_memory)
+        .with_row_index(settings.row_index)
+        .with_cloud_options(cloud)
+        .with_include_file_paths(settings.include_file_paths);
+
+    // Return LazyFrame for lazy evaluation
+    match reader.finish() {
         Ok(lazy_frame) => FfiResult::success_lazy(lazy_frame),
-        Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
+        Err(e) => FfiResult::polars_error(&e),
     }
 }
 
@@ -77,26 +300,38 @@ pub fn dispatch_read_parquet(_handle: PolarsHandle, context: &ExecutionContext)
 
     // Build ScanArgsParquet properly instead of using Default and applying operations later
     let mut scan_args = ScanArgsParquet::default();
+    scan_args.cloud_options = match cloud_options(args.cloud) {
+        Ok(cloud) => cloud,
+        Err(e) => return e,
+    };
 
-    // Handle column selection if specified
-    if !args.columns.is_null() && args.column_count > 0 {
-        let columns = match unsafe { raw_str_array_to_vec(args.columns, args.column_count) } {
-            Ok(cols) => cols,
-            Err(msg) => return FfiResult::error(ERROR_POLARS_OPERATION, msg),
-        };
-        // For now, we'll apply column selection after scan since projection field might not be available
-        // This is still better than the previous approach since we use n_rows properly
-        let lazy_frame = match LazyFrame::scan_parquet(PlPath::new(path_str), scan_args) {
-            Ok(lf) => lf,
-            Err(e) => return FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
-        };
-        
-        // Apply column selection and return LazyFrame for lazy evaluation
-        let column_exprs: Vec<polars::prelude::Expr> = columns.iter().map(|s| polars::prelude::col(s)).collect();
-        let selected_lazy = lazy_frame.select(column_exprs);
-        
-        return FfiResult::success_lazy(selected_lazy);
+    // Hive partitioning: year=2024/month=01/ directories become columns, and filters on
+    // them prune whole directories once predicate pushdown reaches the scan
+    if args.hive_partitioning {
+        scan_args.hive_options.enabled = Some(true);
     }
+    scan_args.hive_options.try_parse_dates = args.try_parse_hive_dates;
+    scan_args.hive_options.schema = match schema_from_overrides(args.hive_schema, args.hive_schema_count) {
+        Ok(schema) => schema,
+        Err(e) => return e,
+    };
+
+    // Row index and source file columns, e.g. to trace rows back across a glob of files
+    scan_args.row_index = match row_index(&args.row_index_name, args.row_index_offset) {
+        Ok(row_index) => row_index,
+        Err(e) => return e,
+    };
+    scan_args.include_file_paths = match optional_name(&args.include_file_paths, "file path column name") {
+        Ok(name) => name,
+        Err(e) => return e,
+    };
+    // Keep the generated columns when a column selection is applied below
+    let generated_columns: Vec<PlSmallStr> = scan_args
+        .row_index
+        .iter()
+        .map(|ri| ri.name.clone())
+        .chain(scan_args.include_file_paths.iter().cloned())
+        .collect();
 
     // Handle row limit if specified - this is the proper way to limit during scan
     if args.n_rows > 0 {
@@ -109,10 +344,584 @@ pub fn dispatch_read_parquet(_handle: PolarsHandle, context: &ExecutionContext)
     } else {
         polars::prelude::ParallelStrategy::None
     };
+    scan_args.low_memory = args.low_memory;
+    scan_args.use_statistics = args.use_statistics;
+    scan_args.rechunk = args.rechunk;
+
+    let predicate = if args.predicate_ops.is_null() || args.predicate_count == 0 {
+        None
+    } else {
+        let ops = unsafe { std::slice::from_raw_parts(args.predicate_ops, args.predicate_count) };
+        match execute_expr_ops(ops) {
+            Ok(expr) => Some(expr),
+            Err(msg) => return FfiResult::error(ERROR_POLARS_OPERATION, msg),
+        }
+    };
+
+    let columns = if !args.columns.is_null() && args.column_count > 0 {
+        match unsafe { raw_str_array_to_vec(args.columns, args.column_count) } {
+            Ok(cols) => cols,
+            Err(msg) => return FfiResult::error(ERROR_POLARS_OPERATION, msg),
+        }
+    } else {
+        Vec::new()
+    };
+
+    let mut lazy_frame = match LazyFrame::scan_parquet(PlPath::new(path_str), scan_args) {
+        Ok(lf) => lf,
+        Err(e) => return FfiResult::polars_error(&e),
+    };
+
+    // The optimizer pushes both into the scan: the predicate prunes row groups using
+    // statistics and the selection limits which columns are decoded. Filtering first
+    // lets the predicate reference columns that are not selected.
+    if let Some(predicate) = predicate {
+        lazy_frame = lazy_frame.filter(predicate);
+    }
+    if !columns.is_empty() {
+        let column_exprs: Vec<polars::prelude::Expr> = generated_columns
+            .iter()
+            .map(|s| polars::prelude::col(s.clone()))
+            .chain(columns.iter().map(|s| polars::prelude::col(s)))
+            .collect();
+        lazy_frame = lazy_frame.select(column_exprs);
+    }
+
+    // Return LazyFrame for lazy evaluation
+    FfiResult::success_lazy(lazy_frame)
+}
+
+/// Arguments for scanning newline-delimited JSON files
+#[repr(C)]
+pub struct ReadNdjsonArgs {
+    pub path: RawStr,               // File path or glob pattern
+    pub infer_schema_length: i64,   // Rows used for inference (0 = default, <0 = all)
+    pub batch_size: usize,          // Rows per parsing batch (0 = default)
+    pub n_rows: usize,              // Number of rows to read (0 for all)
+}
+
+/// Arguments for reading JSON array-of-objects files
+#[repr(C)]
+pub struct ReadJsonArgs {
+    pub path: RawStr,               // File path
+    pub infer_schema_length: i64,   // Rows used for inference (0 = default, <0 = all)
+}
+
+/// Convert the FFI infer_schema_length convention (0 = default, <0 = all rows)
+fn infer_schema_length(value: i64) -> Option<NonZeroUsize> {
+    match value {
+        0 => NonZeroUsize::new(100),
+        n if n < 0 => None,
+        n => NonZeroUsize::new(n as usize),
+    }
+}
+
+/// Dispatch function for scanning NDJSON (glob patterns are expanded)
+pub fn dispatch_read_ndjson(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(context.operation_args as *const ReadNdjsonArgs) };
+
+    let path_str = match unsafe { args.path.as_str() } {
+        Ok(s) => s,
+        Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in path"),
+    };
+
+    match LazyJsonLineReader::new(PlPath::new(path_str))
+        .with_infer_schema_length(infer_schema_length(args.infer_schema_length))
+        .with_batch_size(NonZeroUsize::new(args.batch_size))
+        .with_n_rows(if args.n_rows > 0 { Some(args.n_rows) } else { None })
+        .finish()
+    {
+        Ok(lazy_frame) => FfiResult::success_lazy(lazy_frame),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
+/// Dispatch function for reading a JSON array of objects
+/// JSON documents cannot be scanned lazily, so the file is read eagerly
+pub fn dispatch_read_json(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(context.operation_args as *const ReadJsonArgs) };
+
+    let path_str = match unsafe { args.path.as_str() } {
+        Ok(s) => s,
+        Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in path"),
+    };
+
+    let file = match File::open(path_str) {
+        Ok(f) => f,
+        Err(e) => return FfiResult::error(ERROR_IO, &format!("{}: {}", path_str, e)),
+    };
+
+    match JsonReader::new(file)
+        .with_json_format(JsonFormat::Json)
+        .infer_schema_len(infer_schema_length(args.infer_schema_length))
+        .finish()
+    {
+        Ok(df) => FfiResult::success(df),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
+/// Arguments for reading Arrow IPC files
+#[repr(C)]
+pub struct ReadIpcArgs {
+    pub path: RawStr,     // File path (glob patterns only for scans)
+    pub n_rows: usize,    // Number of rows to read (0 for all)
+    pub memory_map: bool, // Memory-map the file instead of reading it (eager reads only)
+}
+
+/// In-memory byte buffer passed from Go
+#[repr(C)]
+pub struct BufferArgs {
+    pub data: *const u8, // Buffer contents
+    pub len: usize,      // Buffer length in bytes
+}
+
+/// Arrow IPC compression
+pub const IPC_COMPRESSION_NONE: u8 = 0;
+pub const IPC_COMPRESSION_LZ4: u8 = 1;
+pub const IPC_COMPRESSION_ZSTD: u8 = 2;
+
+fn ipc_compression(compression: u8) -> Result<Option<IpcCompression>, String> {
+    match compression {
+        IPC_COMPRESSION_NONE => Ok(None),
+        IPC_COMPRESSION_LZ4 => Ok(Some(IpcCompression::LZ4)),
+        IPC_COMPRESSION_ZSTD => Ok(Some(IpcCompression::default())), // Default codec is ZSTD
+        other => Err(format!("Unknown IPC compression: {}", other)),
+    }
+}
+
+/// Dispatch function for eagerly reading an Arrow IPC (Feather v2) file
+pub fn dispatch_read_ipc(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(context.operation_args as *const ReadIpcArgs) };
+
+    let path_str = match unsafe { args.path.as_str() } {
+        Ok(s) => s,
+        Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in path"),
+    };
+
+    let file = match File::open(path_str) {
+        Ok(f) => f,
+        Err(e) => return FfiResult::error(ERROR_IO, &format!("{}: {}", path_str, e)),
+    };
+
+    let mut reader = IpcReader::new(file)
+        .memory_mapped(if args.memory_map { Some(PathBuf::from(path_str)) } else { None });
+    if args.n_rows > 0 {
+        reader = reader.with_n_rows(Some(args.n_rows));
+    }
+
+    match reader.finish() {
+        Ok(df) => FfiResult::success(df),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
+/// Dispatch function for lazily scanning Arrow IPC files (glob patterns are expanded)
+pub fn dispatch_scan_ipc(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(context.operation_args as *const ReadIpcArgs) };
+
+    let path_str = match unsafe { args.path.as_str() } {
+        Ok(s) => s,
+        Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in path"),
+    };
 
-    // Use LazyFrame scan_parquet with proper ScanArgsParquet - return LazyFrame for lazy evaluation
-    match LazyFrame::scan_parquet(PlPath::new(path_str), scan_args) {
+    let scan_args = ScanArgsIpc {
+        n_rows: if args.n_rows > 0 { Some(args.n_rows) } else { None },
+        ..Default::default()
+    };
+
+    match LazyFrame::scan_ipc(PlPath::new(path_str), scan_args) {
         Ok(lazy_frame) => FfiResult::success_lazy(lazy_frame),
-        Err(e) => FfiResult::error(ERROR_POLARS_OPERATION, &e.to_string()),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
+/// Dispatch function for reading an Arrow IPC stream from an in-memory buffer
+pub fn dispatch_read_ipc_stream(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(context.operation_args as *const BufferArgs) };
+
+    if args.data.is_null() || args.len == 0 {
+        return FfiResult::error(ERROR_NULL_ARGS, "IPC stream buffer cannot be empty");
+    }
+
+    let bytes = unsafe { std::slice::from_raw_parts(args.data, args.len) };
+    match IpcStreamReader::new(Cursor::new(bytes)).finish() {
+        Ok(df) => FfiResult::success(df),
+        Err(e) => FfiResult::polars_error(&e),
     }
 }
+
+/// Write a DataFrame to an Arrow IPC file
+/// Returns 0 on success, or an error code with error_message set (free with free_string)
+#[no_mangle]
+pub extern "C" fn dataframe_write_ipc(
+    handle: usize,
+    path: RawStr,
+    compression: u8,
+    error_message: *mut *mut c_char,
+) -> c_int {
+    catch_panic(
+        || {
+            if handle == 0 {
+                write_error_message(error_message, "Handle cannot be null");
+                return ERROR_NULL_HANDLE;
+            }
+
+            let path_str = match unsafe { path.as_str() } {
+                Ok(s) => s,
+                Err(_) => {
+                    write_error_message(error_message, "Invalid UTF-8 in path");
+                    return ERROR_INVALID_UTF8;
+                }
+            };
+
+            let compression = match ipc_compression(compression) {
+                Ok(c) => c,
+                Err(msg) => {
+                    write_error_message(error_message, &msg);
+                    return ERROR_POLARS_OPERATION;
+                }
+            };
+
+            let file = match File::create(path_str) {
+                Ok(f) => f,
+                Err(e) => {
+                    write_error_message(error_message, &format!("{}: {}", path_str, e));
+                    return ERROR_IO;
+                }
+            };
+
+            let df = unsafe { &*(handle as *const DataFrame) };
+            let mut df_clone = df.clone();
+            match IpcWriter::new(file).with_compression(compression).finish(&mut df_clone) {
+                Ok(_) => 0,
+                Err(e) => {
+                    write_error_message(error_message, &e.to_string());
+                    polars_error_code(&e)
+                }
+            }
+        },
+        |message| {
+            write_error_message(error_message, &message);
+            ERROR_PANIC
+        },
+    )
+}
+
+/// Hand a byte buffer to C; release it with free_buffer
+pub(crate) fn into_raw_buffer(bytes: Vec<u8>, out_len: *mut usize) -> *mut u8 {
+    let boxed = bytes.into_boxed_slice();
+    if !out_len.is_null() {
+        unsafe { *out_len = boxed.len() };
+    }
+    Box::into_raw(boxed) as *mut u8
+}
+
+/// Free a buffer returned by one of the dataframe_to_* buffer functions
+#[no_mangle]
+pub extern "C" fn free_buffer(data: *mut u8, len: usize) {
+    if !data.is_null() {
+        unsafe {
+            let _ = Box::from_raw(std::ptr::slice_from_raw_parts_mut(data, len));
+        }
+    }
+}
+
+/// Serialize a DataFrame as an Arrow IPC stream into a new buffer
+/// Encoding stops with ERROR_CANCELLED once token (0 = none) is cancelled
+/// Returns null with error_code and error_message set on failure
+#[no_mangle]
+pub extern "C" fn dataframe_to_ipc_stream(
+    handle: usize,
+    compression: u8,
+    token: usize,
+    out_len: *mut usize,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut u8 {
+    let fail = |code: c_int, message: &str| {
+        write_error(error_code, error_message, code, message);
+        std::ptr::null_mut()
+    };
+
+    with_cancel_token(token, || catch_panic(
+        || {
+            if handle == 0 {
+                return fail(ERROR_NULL_HANDLE, "Handle cannot be null");
+            }
+
+            let compression = match ipc_compression(compression) {
+                Ok(c) => c,
+                Err(msg) => return fail(ERROR_INVALID_OPERATION, &msg),
+            };
+
+            let df = unsafe { &*(handle as *const DataFrame) };
+            let mut df_clone = df.clone();
+            let mut buffer = CancellableWriter(Vec::new());
+            let result = IpcStreamWriter::new(&mut buffer)
+                .with_compression(compression)
+                .finish(&mut df_clone);
+            encoded_buffer(result, buffer.0, out_len, fail)
+        },
+        |message| fail(ERROR_PANIC, &message),
+    ))
+}
+
+/// Arguments for reading CSV from an in-memory buffer
+/// options.path and options.with_glob are ignored
+#[repr(C)]
+pub struct ReadCsvBufferArgs {
+    pub buffer: BufferArgs,    // CSV text
+    pub options: ReadCsvArgs,  // Parsing options
+}
+
+/// Borrow the bytes of a BufferArgs, rejecting empty buffers
+fn buffer_bytes<'a>(args: &'a BufferArgs, what: &str) -> Result<&'a [u8], FfiResult> {
+    if args.data.is_null() || args.len == 0 {
+        return Err(FfiResult::error(ERROR_NULL_ARGS, &format!("{} buffer cannot be empty", what)));
+    }
+    Ok(unsafe { std::slice::from_raw_parts(args.data, args.len) })
+}
+
+/// Dispatch function for eagerly reading CSV from an in-memory buffer
+pub fn dispatch_read_csv_buffer(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(context.operation_args as *const ReadCsvBufferArgs) };
+
+    let bytes = match buffer_bytes(&args.buffer, "CSV") {
+        Ok(b) => b,
+        Err(e) => return e,
+    };
+
+    let settings = match csv_settings(&args.options) {
+        Ok(settings) => settings,
+        Err(e) => return e,
+    };
+
+    let parse_options = CsvParseOptions::default()
+        .with_separator(settings.separator)
+        .with_quote_char(Some(settings.quote_char))
+        .with_comment_prefix(settings.comment_prefix.as_deref())
+        .with_null_values(settings.null_values)
+        .with_try_parse_dates(args.options.try_parse_dates)
+        .with_encoding(settings.encoding);
+
+    match CsvReadOptions::default()
+        .with_has_header(args.options.has_header)
+        .with_skip_rows(args.options.skip_rows)
+        .with_n_rows(settings.n_rows)
+        .with_schema_overwrite(settings.schema_overrides)
+        .with_infer_schema_length(settings.infer_schema_length)
+        .with_ignore_errors(args.options.ignore_errors)
+        .with_low_memory(args.options.low_memory)
+        .with_row_index(settings.row_index)
+        .with_parse_options(parse_options)
+        .into_reader_with_file_handle(Cursor::new(bytes))
+        .finish()
+    {
+        Ok(df) => FfiResult::success(df),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
+/// Dispatch function for eagerly reading a Parquet file held in an in-memory buffer
+pub fn dispatch_read_parquet_buffer(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(context.operation_args as *const BufferArgs) };
+
+    let bytes = match buffer_bytes(args, "Parquet") {
+        Ok(b) => b,
+        Err(e) => return e,
+    };
+
+    match ParquetReader::new(Cursor::new(bytes)).finish() {
+        Ok(df) => FfiResult::success(df),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
+/// Dispatch function for eagerly reading NDJSON from an in-memory buffer
+pub fn dispatch_read_ndjson_buffer(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(context.operation_args as *const BufferArgs) };
+
+    let bytes = match buffer_bytes(args, "NDJSON") {
+        Ok(b) => b,
+        Err(e) => return e,
+    };
+
+    match JsonReader::new(Cursor::new(bytes))
+        .with_json_format(JsonFormat::JsonLines)
+        .infer_schema_len(infer_schema_length(0))
+        .finish()
+    {
+        Ok(df) => FfiResult::success(df),
+        Err(e) => FfiResult::polars_error(&e),
+    }
+}
+
+/// Output formats for dataframe_to_buffer
+pub const BUFFER_FORMAT_CSV: u8 = 0;
+pub const BUFFER_FORMAT_PARQUET: u8 = 1;
+
+/// Serialize a DataFrame as CSV or Parquet into a new buffer (release with free_buffer)
+/// Encoding stops with ERROR_CANCELLED once token (0 = none) is cancelled
+/// Returns null with error_code and error_message set on failure
+#[no_mangle]
+pub extern "C" fn dataframe_to_buffer(
+    handle: usize,
+    format: u8,
+    token: usize,
+    out_len: *mut usize,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut u8 {
+    let fail = |code: c_int, message: &str| {
+        write_error(error_code, error_message, code, message);
+        std::ptr::null_mut()
+    };
+
+    with_cancel_token(token, || catch_panic(
+        || {
+            if handle == 0 {
+                return fail(ERROR_NULL_HANDLE, "Handle cannot be null");
+            }
+
+            let df = unsafe { &*(handle as *const DataFrame) };
+            let mut df_clone = df.clone();
+            let mut buffer = CancellableWriter(Vec::new());
+            let result = match format {
+                BUFFER_FORMAT_CSV => CsvWriter::new(&mut buffer).finish(&mut df_clone),
+                BUFFER_FORMAT_PARQUET => ParquetWriter::new(&mut buffer).finish(&mut df_clone).map(|_| ()),
+                other => return fail(ERROR_INVALID_OPERATION, &format!("Unknown buffer format: {}", other)),
+            };
+            encoded_buffer(result, buffer.0, out_len, fail)
+        },
+        |message| fail(ERROR_PANIC, &message),
+    ))
+}
+
+/// Hand an encoded buffer to the caller, or report why encoding stopped
+fn encoded_buffer<T>(
+    result: PolarsResult<T>,
+    buffer: Vec<u8>,
+    out_len: *mut usize,
+    fail: impl Fn(c_int, &str) -> *mut u8,
+) -> *mut u8 {
+    match result {
+        Ok(_) => into_raw_buffer(buffer, out_len),
+        // The failed write of a cancelled encode surfaces as an IO error
+        Err(_) if is_cancelled() => fail(ERROR_CANCELLED, "Execution was cancelled"),
+        Err(e) => fail(polars_error_code(&e), &e.to_string()),
+    }
+}
+
+/// Hive encoding of a partition value: nulls use Hive's default partition name and
+/// characters that would break the key=value path segment are percent-encoded
+fn hive_partition_value(value: &AnyValue) -> String {
+    if value.is_null() {
+        return "__HIVE_DEFAULT_PARTITION__".to_string();
+    }
+
+    let mut encoded = String::new();
+    for c in value.str_value().chars() {
+        if c.is_ascii_alphanumeric() || matches!(c, '-' | '_' | '.' | '~' | ' ') || !c.is_ascii() {
+            encoded.push(c);
+        } else {
+            encoded.push_str(&format!("%{:02X}", c as u32));
+        }
+    }
+    encoded
+}
+
+/// Create the next free NNNNNNNN.parquet file in dir, so writing to an existing dataset
+/// adds files next to the earlier ones instead of replacing them
+fn create_part_file(dir: &Path) -> std::io::Result<File> {
+    let mut index: u64 = 0;
+    for entry in std::fs::read_dir(dir)? {
+        let name = entry?.file_name();
+        let existing = name.to_str().and_then(|n| n.strip_suffix(".parquet")).and_then(|n| n.parse::<u64>().ok());
+        if let Some(existing) = existing {
+            index = index.max(existing + 1);
+        }
+    }
+    loop {
+        // create_new fails instead of truncating a file another writer created meanwhile
+        match OpenOptions::new().write(true).create_new(true).open(dir.join(format!("{:08}.parquet", index))) {
+            Err(e) if e.kind() == std::io::ErrorKind::AlreadyExists => index += 1,
+            result => return result,
+        }
+    }
+}
+
+/// Write a DataFrame as a Hive-partitioned Parquet dataset
+/// Each distinct combination of partition values is written to a new file in
+/// dir/key1=value1/key2=value2/ without the partition columns: 00000000.parquet for a
+/// new partition, otherwise the next number after the files already there
+/// Returns 0 on success, or an error code with error_message set (free with free_string)
+#[no_mangle]
+pub extern "C" fn dataframe_write_parquet_partitioned(
+    handle: usize,
+    dir: RawStr,
+    partition_by: *const RawStr,
+    partition_count: usize,
+    error_message: *mut *mut c_char,
+) -> c_int {
+    catch_panic(
+        || {
+            if handle == 0 {
+                write_error_message(error_message, "Handle cannot be null");
+                return ERROR_NULL_HANDLE;
+            }
+
+            let dir_str = match unsafe { dir.as_str() } {
+                Ok("") => {
+                    write_error_message(error_message, "Directory cannot be empty");
+                    return ERROR_NULL_ARGS;
+                }
+                Ok(s) => s,
+                Err(_) => {
+                    write_error_message(error_message, "Invalid UTF-8 in directory");
+                    return ERROR_INVALID_UTF8;
+                }
+            };
+
+            let keys = match unsafe { raw_str_array_to_vec(partition_by, partition_count) } {
+                Ok(keys) if !keys.is_empty() => keys,
+                Ok(_) => {
+                    write_error_message(error_message, "At least one partition column is required");
+                    return ERROR_NULL_ARGS;
+                }
+                Err(msg) => {
+                    write_error_message(error_message, msg);
+                    return ERROR_INVALID_UTF8;
+                }
+            };
+
+            let df = unsafe { &*(handle as *const DataFrame) };
+            let result = (|| -> PolarsResult<()> {
+                for partition in df.partition_by_stable(keys.iter().map(|k| k.as_str()), true)? {
+                    let mut path = PathBuf::from(dir_str);
+                    for key in &keys {
+                        let value = partition.column(key)?.get(0)?;
+                        path.push(format!("{}={}", key, hive_partition_value(&value)));
+                    }
+                    std::fs::create_dir_all(&path)?;
+
+                    let mut data = partition.drop_many(keys.iter().map(|k| k.as_str()));
+                    ParquetWriter::new(create_part_file(&path)?).finish(&mut data)?;
+                }
+                Ok(())
+            })();
+
+            match result {
+                Ok(_) => 0,
+                Err(e) => {
+                    write_error_message(error_message, &e.to_string());
+                    polars_error_code(&e)
+                }
+            }
+        },
+        |message| {
+            write_error_message(error_message, &message);
+            ERROR_PANIC
+        },
+    )
+}
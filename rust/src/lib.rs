@@ -1,29 +1,67 @@
-use polars::prelude::{DataFrame, LazyFrame, LazyGroupBy};
+use polars::prelude::{DataFrame, LazyFrame, LazyGroupBy, PolarsError};
+use std::any::Any;
 use std::ffi::CString;
 use std::os::raw::{c_char, c_int};
 use std::ptr;
+use std::sync::Arc;
 
 // Module declarations
+mod cancel;
 mod dataframe;
 mod execution;
 mod expr;
 mod io;
+mod metadata;
 mod opcodes;
+mod plan;
+mod sql_params;
+mod threads;
 mod types;
+mod udf;
 
 // Re-export public items
+pub use cancel::*;
 pub use dataframe::*;
-pub use execution::{execute_expr_ops, execute_operations, ExecutionContext};
+pub use execution::{
+    execute_expr_ops, execute_operations, execute_subplan, explain_plan, ExecutionContext, SubPlan,
+};
 pub use expr::*;
 pub use io::*;
+pub use metadata::*;
 pub use opcodes::*;
+pub use plan::*;
+pub use sql_params::*;
+pub use threads::*;
 pub use types::*;
+pub use udf::*;
 
 // Error codes
 pub const ERROR_NULL_HANDLE: c_int = 1;
 pub const ERROR_NULL_ARGS: c_int = 2;
 pub const ERROR_INVALID_UTF8: c_int = 3;
-pub const ERROR_POLARS_OPERATION: c_int = 4;
+pub const ERROR_POLARS_OPERATION: c_int = 4;  // Any Polars error without a more specific code
+pub const ERROR_COLUMN_NOT_FOUND: c_int = 5;
+pub const ERROR_SCHEMA_MISMATCH: c_int = 6;
+pub const ERROR_COMPUTE: c_int = 7;
+pub const ERROR_INVALID_OPERATION: c_int = 8;
+pub const ERROR_IO: c_int = 9;
+pub const ERROR_PANIC: c_int = 10;    // A Rust panic caught at the FFI boundary
+pub const ERROR_CANCELLED: c_int = 11; // Stopped by a cancellation token
+
+/// Map a PolarsError variant to the FFI error code the Go side turns into a sentinel error
+pub fn polars_error_code(error: &PolarsError) -> c_int {
+    match error {
+        PolarsError::ColumnNotFound(_)
+        | PolarsError::SchemaFieldNotFound(_)
+        | PolarsError::StructFieldNotFound(_) => ERROR_COLUMN_NOT_FOUND,
+        PolarsError::SchemaMismatch(_) | PolarsError::ShapeMismatch(_) => ERROR_SCHEMA_MISMATCH,
+        PolarsError::ComputeError(_) => ERROR_COMPUTE,
+        PolarsError::InvalidOperation(_) | PolarsError::Duplicate(_) => ERROR_INVALID_OPERATION,
+        PolarsError::IO { .. } => ERROR_IO,
+        PolarsError::Context { error, .. } => polars_error_code(error),
+        _ => ERROR_POLARS_OPERATION,
+    }
+}
 
 /// Zero-copy string representation for FFI
 #[repr(C)]
@@ -60,9 +98,10 @@ pub struct FfiResult {
 
 impl FfiResult {
     /// Create a successful result with a new DataFrame
+    /// DataFrame handles are Arc pointers so Go can share one across concurrent
+    /// executions; see retain_dataframe and release_dataframe
     pub fn success(df: DataFrame) -> Self {
-        let boxed_df = Box::new(df);
-        let handle = Box::into_raw(boxed_df) as usize;
+        let handle = Arc::into_raw(Arc::new(df)) as usize;
         Self {
             polars_handle: PolarsHandle::new(handle, ContextType::DataFrame),
             error_code: 0,
@@ -115,6 +154,11 @@ impl FfiResult {
         }
     }
 
+    /// Create an error result from a Polars error, keeping its category in the code
+    pub fn polars_error(error: &PolarsError) -> Self {
+        Self::error(polars_error_code(error), &error.to_string())
+    }
+
     /// Create an error result
     pub fn error(code: c_int, message: &str) -> Self {
         let c_message = match CString::new(message) {
@@ -131,6 +175,46 @@ impl FfiResult {
     }
 }
 
+/// Store an error message in a C out-parameter (caller frees with free_string)
+/// Used by FFI functions that don't return an FfiResult
+pub(crate) fn write_error_message(error_message: *mut *mut c_char, message: &str) {
+    if error_message.is_null() {
+        return;
+    }
+    let c_message = CString::new(message).unwrap_or_else(|_| CString::new("Unknown error").unwrap());
+    unsafe { *error_message = c_message.into_raw() };
+}
+
+/// Store an error code and message in C out-parameters (message freed with free_string)
+/// Used by FFI functions that return a pointer or value and report failures on the side
+pub(crate) fn write_error(error_code: *mut c_int, error_message: *mut *mut c_char, code: c_int, message: &str) {
+    if !error_code.is_null() {
+        unsafe { *error_code = code };
+    }
+    write_error_message(error_message, message);
+}
+
+/// Run the body of an FFI entry point, turning a panic into on_panic(message)
+/// Unwinding into Go is undefined behaviour and takes down the whole process, so every
+/// exported function that can reach Polars code runs its body through this
+pub(crate) fn catch_panic<T>(body: impl FnOnce() -> T, on_panic: impl FnOnce(String) -> T) -> T {
+    match std::panic::catch_unwind(std::panic::AssertUnwindSafe(body)) {
+        Ok(value) => value,
+        Err(payload) => on_panic(panic_message(payload.as_ref())),
+    }
+}
+
+/// Extract the message from a panic payload (panic! with a literal or a format string)
+fn panic_message(payload: &(dyn Any + Send)) -> String {
+    if let Some(message) = payload.downcast_ref::<&str>() {
+        message.to_string()
+    } else if let Some(message) = payload.downcast_ref::<String>() {
+        message.clone()
+    } else {
+        "Rust panic with a non-string payload".to_string()
+    }
+}
+
 /// Window function arguments
 #[repr(C)]
 pub struct WindowArgs {
@@ -162,7 +246,7 @@ pub enum JoinType {
 /// Arguments for join operations
 #[repr(C)]
 pub struct JoinArgs {
-    pub other_handle: usize,     // Handle to the right DataFrame
+    pub other: SubPlan,          // Right side, executed lazily in the same call
     pub left_on: *const RawStr,  // Left join columns 
     pub right_on: *const RawStr, // Right join columns
     pub column_count: usize,     // Number of join columns
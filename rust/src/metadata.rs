@@ -0,0 +1,161 @@
+use crate::{
+    catch_panic, write_error, RawStr, ERROR_INVALID_UTF8, ERROR_IO, ERROR_NULL_ARGS, ERROR_PANIC,
+    ERROR_POLARS_OPERATION,
+};
+use polars_parquet::parquet::metadata::FileMetadata;
+use polars_parquet::parquet::statistics::Statistics;
+use polars_parquet::read::read_metadata;
+use serde::Serialize;
+use std::collections::BTreeMap;
+use std::ffi::CString;
+use std::fs::File;
+use std::io::BufReader;
+use std::os::raw::{c_char, c_int};
+use std::ptr;
+
+/// Parquet footer metadata serialized to JSON for the Go side
+#[derive(Serialize)]
+struct ParquetMetadataJson {
+    num_rows: usize,
+    version: i32,
+    created_by: Option<String>,
+    row_groups: Vec<RowGroupJson>,
+    key_value_metadata: BTreeMap<String, String>,
+}
+
+#[derive(Serialize)]
+struct RowGroupJson {
+    num_rows: usize,
+    total_byte_size: usize,
+    compressed_size: usize,
+    columns: Vec<ColumnChunkJson>,
+}
+
+#[derive(Serialize)]
+struct ColumnChunkJson {
+    path: String,
+    physical_type: String,
+    compression: String,
+    compressed_size: i64,
+    uncompressed_size: i64,
+    num_values: i64,
+    null_count: Option<i64>,
+    min: Option<String>,
+    max: Option<String>,
+}
+
+/// Render min/max statistics as strings of their physical (stored) values
+fn render_statistics(stats: &Statistics) -> (Option<i64>, Option<String>, Option<String>) {
+    fn show<T: ToString>(v: &Option<T>) -> Option<String> {
+        v.as_ref().map(|v| v.to_string())
+    }
+    fn show_bytes(v: &Option<Vec<u8>>) -> Option<String> {
+        v.as_ref().map(|v| String::from_utf8_lossy(v).into_owned())
+    }
+
+    match stats {
+        Statistics::Binary(s) => (s.null_count, show_bytes(&s.min_value), show_bytes(&s.max_value)),
+        Statistics::FixedLen(s) => (s.null_count, show_bytes(&s.min_value), show_bytes(&s.max_value)),
+        Statistics::Boolean(s) => (s.null_count, show(&s.min_value), show(&s.max_value)),
+        Statistics::Int32(s) => (s.null_count, show(&s.min_value), show(&s.max_value)),
+        Statistics::Int64(s) => (s.null_count, show(&s.min_value), show(&s.max_value)),
+        Statistics::Float(s) => (s.null_count, show(&s.min_value), show(&s.max_value)),
+        Statistics::Double(s) => (s.null_count, show(&s.min_value), show(&s.max_value)),
+        // Legacy INT96 timestamps have no meaningful ordering
+        Statistics::Int96(s) => (s.null_count, None, None),
+    }
+}
+
+fn metadata_to_json(metadata: &FileMetadata) -> ParquetMetadataJson {
+    let row_groups = metadata
+        .row_groups
+        .iter()
+        .map(|rg| RowGroupJson {
+            num_rows: rg.num_rows(),
+            total_byte_size: rg.total_byte_size(),
+            compressed_size: rg.compressed_size(),
+            columns: rg
+                .parquet_columns()
+                .iter()
+                .map(|column| {
+                    let (null_count, min, max) = match column.statistics() {
+                        Some(Ok(stats)) => render_statistics(&stats),
+                        _ => (None, None, None),
+                    };
+                    ColumnChunkJson {
+                        path: column.descriptor().path_in_schema.join("."),
+                        physical_type: format!("{:?}", column.physical_type()),
+                        compression: format!("{:?}", column.compression()),
+                        compressed_size: column.compressed_size(),
+                        uncompressed_size: column.uncompressed_size(),
+                        num_values: column.num_values(),
+                        null_count,
+                        min,
+                        max,
+                    }
+                })
+                .collect(),
+        })
+        .collect();
+
+    let key_value_metadata = metadata
+        .key_value_metadata
+        .iter()
+        .flatten()
+        .map(|kv| (kv.key.clone(), kv.value.clone().unwrap_or_default()))
+        .collect();
+
+    ParquetMetadataJson {
+        num_rows: metadata.num_rows,
+        version: metadata.version,
+        created_by: metadata.created_by.clone(),
+        row_groups,
+        key_value_metadata,
+    }
+}
+
+/// Read the footer metadata of a Parquet file without scanning any data pages
+/// Returns a JSON document (free with free_string), or null with error_code and
+/// error_message set on failure
+#[no_mangle]
+pub extern "C" fn read_parquet_metadata(
+    path: RawStr,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut c_char {
+    let fail = |code: c_int, message: &str| {
+        write_error(error_code, error_message, code, message);
+        ptr::null_mut()
+    };
+
+    catch_panic(
+        || {
+            let path_str = match unsafe { path.as_str() } {
+                Ok("") => return fail(ERROR_NULL_ARGS, "Path cannot be empty"),
+                Ok(s) => s,
+                Err(_) => return fail(ERROR_INVALID_UTF8, "Invalid UTF-8 in path"),
+            };
+
+            let file = match File::open(path_str) {
+                Ok(f) => f,
+                Err(e) => return fail(ERROR_IO, &format!("{}: {}", path_str, e)),
+            };
+
+            let metadata = match read_metadata(&mut BufReader::new(file)) {
+                Ok(m) => m,
+                Err(e) => return fail(ERROR_POLARS_OPERATION, &format!("{}: {}", path_str, e)),
+            };
+
+            let json = match serde_json::to_string(&metadata_to_json(&metadata)) {
+                Ok(j) => j,
+                Err(e) => return fail(ERROR_POLARS_OPERATION, &e.to_string()),
+            };
+
+            match CString::new(json) {
+                Ok(c_string) => c_string.into_raw(),
+                Err(_) => fail(ERROR_POLARS_OPERATION, "Metadata contains interior NUL byte"),
+            }
+        },
+        |message| fail(ERROR_PANIC, &message),
+    )
+}
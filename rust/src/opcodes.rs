@@ -1,4 +1,4 @@
-use crate::RawStr;
+use crate::{Literal, RawStr};
 /// Opcodes for DataFrame and Expression operations
 /// These replace function pointers for cleaner dispatch and context handling
 ///
@@ -28,6 +28,25 @@ pub enum OpCode {
     Query = 16,
     Join = 17,
     FromMemory = 18,
+    Describe = 19,
+    NullCount = 20,
+    ReadNdjson = 21,
+    ReadJson = 22,
+    ReadIpc = 23,
+    ScanIpc = 24,
+    ReadIpcStream = 25,
+    ReadCsvBuffer = 26,
+    ReadParquetBuffer = 27,
+    ReadNdjsonBuffer = 28,
+
+    // Testing support
+    RaisePanic = 29,
+
+    // SQL over several named tables
+    SqlContext = 30,
+
+    // Lazy query from a serialized plan
+    DeserializePlan = 31,
 
     // Expression operations (stack-based)
     ExprColumn = 100,
@@ -98,6 +117,39 @@ pub enum OpCode {
     // Cast operations
     ExprCast = 200,       // Cast expression to specified data type
 
+    // Rolling window operations (fixed row count or duration via RollingArgs.by)
+    ExprRollingMean = 210,
+    ExprRollingSum = 211,
+    ExprRollingMin = 212,
+    ExprRollingMax = 213,
+    ExprRollingStd = 214,
+    ExprRollingMedian = 215,
+    ExprRollingQuantile = 216,
+
+    // Cumulative and difference operations
+    ExprCumSum = 220,
+    ExprCumMin = 221,
+    ExprCumMax = 222,
+    ExprCumProd = 223,
+    ExprCumCount = 224,
+    ExprDiff = 225,
+    ExprPctChange = 226,
+
+    // Extended aggregations (usable in Agg and Select)
+    ExprQuantile = 230,
+    ExprMode = 231,
+    ExprApproxNUnique = 232,
+    ExprArgMin = 233,
+    ExprArgMax = 234,
+    ExprSkew = 235,
+    ExprKurtosis = 236,
+    ExprProduct = 237,
+    ExprImplode = 238,
+    ExprAggGroups = 239,
+
+    // User-defined functions (Go callbacks)
+    ExprMapBatches = 240,
+
     // Error operation for fluent API error handling
     Error = 999,
 }
@@ -124,6 +176,19 @@ impl OpCode {
             16 => Some(OpCode::Query),
             17 => Some(OpCode::Join),
             18 => Some(OpCode::FromMemory),
+            19 => Some(OpCode::Describe),
+            20 => Some(OpCode::NullCount),
+            21 => Some(OpCode::ReadNdjson),
+            22 => Some(OpCode::ReadJson),
+            23 => Some(OpCode::ReadIpc),
+            24 => Some(OpCode::ScanIpc),
+            25 => Some(OpCode::ReadIpcStream),
+            26 => Some(OpCode::ReadCsvBuffer),
+            27 => Some(OpCode::ReadParquetBuffer),
+            28 => Some(OpCode::ReadNdjsonBuffer),
+            29 => Some(OpCode::RaisePanic),
+            30 => Some(OpCode::SqlContext),
+            31 => Some(OpCode::DeserializePlan),
             100 => Some(OpCode::ExprColumn),
             101 => Some(OpCode::ExprLiteral),
             102 => Some(OpCode::ExprAdd),
@@ -187,6 +252,35 @@ impl OpCode {
             192 => Some(OpCode::ExprOtherwise),
             // Cast
             200 => Some(OpCode::ExprCast),
+            // Rolling windows
+            210 => Some(OpCode::ExprRollingMean),
+            211 => Some(OpCode::ExprRollingSum),
+            212 => Some(OpCode::ExprRollingMin),
+            213 => Some(OpCode::ExprRollingMax),
+            214 => Some(OpCode::ExprRollingStd),
+            215 => Some(OpCode::ExprRollingMedian),
+            216 => Some(OpCode::ExprRollingQuantile),
+            // Cumulative and difference
+            220 => Some(OpCode::ExprCumSum),
+            221 => Some(OpCode::ExprCumMin),
+            222 => Some(OpCode::ExprCumMax),
+            223 => Some(OpCode::ExprCumProd),
+            224 => Some(OpCode::ExprCumCount),
+            225 => Some(OpCode::ExprDiff),
+            226 => Some(OpCode::ExprPctChange),
+            // Extended aggregations
+            230 => Some(OpCode::ExprQuantile),
+            231 => Some(OpCode::ExprMode),
+            232 => Some(OpCode::ExprApproxNUnique),
+            233 => Some(OpCode::ExprArgMin),
+            234 => Some(OpCode::ExprArgMax),
+            235 => Some(OpCode::ExprSkew),
+            236 => Some(OpCode::ExprKurtosis),
+            237 => Some(OpCode::ExprProduct),
+            238 => Some(OpCode::ExprImplode),
+            239 => Some(OpCode::ExprAggGroups),
+            // User-defined functions
+            240 => Some(OpCode::ExprMapBatches),
             999 => Some(OpCode::Error),
             _ => None,
         }
@@ -330,6 +424,8 @@ pub struct LimitArgs {
 #[derive(Clone, Copy)]
 pub struct QueryArgs {
     pub sql: RawStr,
+    pub params: *const Literal, // Values for the $n / ? placeholders in sql
+    pub param_count: usize,     // 0 = run sql as written
 }
 
 /// Arguments for SQL expression operations
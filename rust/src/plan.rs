@@ -0,0 +1,83 @@
+use crate::execution::take_ffi_error;
+use crate::{
+    catch_panic, execute_subplan, write_error, ExecutionContext, FfiResult, PolarsHandle, RawStr, SubPlan,
+    ERROR_INVALID_OPERATION, ERROR_INVALID_UTF8, ERROR_NULL_ARGS, ERROR_PANIC, ERROR_POLARS_OPERATION,
+};
+use polars::prelude::{DslPlan, LazyFrame};
+use std::ffi::{CStr, CString};
+use std::os::raw::{c_char, c_int};
+
+/// Polars release the plan JSON belongs to, as resolved in Cargo.lock (see build.rs);
+/// Polars does not read plans written by other releases
+const POLARS_VERSION: &CStr = match CStr::from_bytes_with_nul(concat!(env!("FIRN_POLARS_VERSION"), "\0").as_bytes()) {
+    Ok(version) => version,
+    Err(_) => panic!("FIRN_POLARS_VERSION contains a NUL byte"),
+};
+
+/// Arguments for the DeserializePlan operation
+#[repr(C)]
+pub struct PlanArgs {
+    pub json: RawStr, // Plan JSON from serialize_plan
+}
+
+/// Polars release recorded in serialized plans (static, do not free)
+#[no_mangle]
+pub extern "C" fn plan_polars_version() -> *const c_char {
+    POLARS_VERSION.as_ptr()
+}
+
+/// Serialize the logical plan of a sub-plan as JSON without collecting it
+/// Collected DataFrames in the plan are embedded with their data; plans calling Go
+/// functions cannot be serialized
+/// Returns the JSON (free with free_string), or null with error_code and error_message set
+#[no_mangle]
+pub extern "C" fn serialize_plan(
+    plan: SubPlan,
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> *mut c_char {
+    let fail = |code: c_int, message: &str| {
+        write_error(error_code, error_message, code, message);
+        std::ptr::null_mut()
+    };
+
+    catch_panic(
+        || {
+            let lazy_frame = match execute_subplan(&plan) {
+                Ok(lf) => lf,
+                Err(result) => {
+                    let (code, message) = take_ffi_error(result);
+                    return fail(code, &message);
+                }
+            };
+
+            match serde_json::to_string(&lazy_frame.logical_plan) {
+                Ok(json) => match CString::new(json) {
+                    Ok(c_string) => c_string.into_raw(),
+                    Err(_) => fail(ERROR_POLARS_OPERATION, "Plan contains interior NUL byte"),
+                },
+                Err(e) => fail(ERROR_INVALID_OPERATION, &format!("Plan cannot be serialized: {}", e)),
+            }
+        },
+        |message| fail(ERROR_PANIC, &message),
+    )
+}
+
+/// DeserializePlan operation - starts a lazy query from plan JSON written by serialize_plan
+pub fn dispatch_deserialize_plan(_handle: PolarsHandle, context: &ExecutionContext) -> FfiResult {
+    if context.operation_args == 0 {
+        return FfiResult::error(ERROR_NULL_ARGS, "PlanArgs cannot be null");
+    }
+
+    let args = unsafe { &*(context.operation_args as *const PlanArgs) };
+
+    let json = match unsafe { args.json.as_str() } {
+        Ok(s) => s,
+        Err(_) => return FfiResult::error(ERROR_INVALID_UTF8, "Invalid UTF-8 in plan JSON"),
+    };
+
+    match serde_json::from_str::<DslPlan>(json) {
+        Ok(plan) => FfiResult::success_lazy(LazyFrame::from(plan)),
+        Err(e) => FfiResult::error(ERROR_INVALID_OPERATION, &format!("Invalid plan: {}", e)),
+    }
+}
@@ -0,0 +1,111 @@
+use crate::{FfiResult, Literal, ERROR_INVALID_OPERATION, ERROR_INVALID_UTF8, ERROR_POLARS_OPERATION};
+use sqlparser::ast::{visit_expressions_mut, Expr, Statement, Value};
+use sqlparser::dialect::GenericDialect;
+use sqlparser::parser::{Parser, ParserOptions};
+use sqlparser::tokenizer::{Token, Tokenizer};
+use std::ops::ControlFlow;
+
+/// Parse a single-statement SQL query and replace its `$n` or `?` placeholders with the
+/// values of params
+/// Each value becomes a typed literal in the parsed statement, which the caller runs with
+/// SQLContext::execute_statement; the statement is never rendered back to SQL text, so a
+/// string parameter is always a single SQL string and never query text. sqlparser must be
+/// the version polars-sql uses, or the Statement types differ.
+pub fn bind_query_params(sql: &str, params: &[Literal]) -> Result<Statement, FfiResult> {
+    let dialect = GenericDialect {};
+    let mut tokens = Tokenizer::new(&dialect, sql)
+        .tokenize()
+        .map_err(|e| FfiResult::error(ERROR_POLARS_OPERATION, &format!("SQL parse error: {}", e)))?;
+
+    // Number `?` placeholders in order of appearance so both styles bind the same way
+    let mut positional = 0;
+    let mut numbered = false;
+    for token in tokens.iter_mut() {
+        if let Token::Placeholder(name) = token {
+            if name == "?" {
+                positional += 1;
+                *name = format!("${}", positional);
+            } else {
+                numbered = true;
+            }
+        }
+    }
+    if positional > 0 && numbered {
+        return Err(invalid("a query cannot mix ? and $n placeholders".to_string()));
+    }
+
+    let values = params
+        .iter()
+        .enumerate()
+        .map(|(i, param)| param_value(i + 1, param))
+        .collect::<Result<Vec<_>, _>>()?;
+
+    // Same options as SQLContext::execute
+    let mut statements = Parser::new(&dialect)
+        .with_options(ParserOptions::new().with_trailing_commas(true))
+        .with_tokens(tokens)
+        .parse_statements()
+        .map_err(|e| FfiResult::error(ERROR_POLARS_OPERATION, &format!("SQL parse error: {}", e)))?;
+    if statements.len() != 1 {
+        return Err(invalid(format!("a query must be one statement, got {}", statements.len())));
+    }
+
+    let mut used = vec![false; values.len()];
+    let flow = visit_expressions_mut(&mut statements, |expr| {
+        if let Expr::Value(Value::Placeholder(name)) = expr {
+            let index = match name.strip_prefix('$').and_then(|n| n.parse::<usize>().ok()) {
+                Some(index) if index >= 1 && index <= values.len() => index - 1,
+                _ => {
+                    return ControlFlow::Break(format!(
+                        "placeholder {} has no parameter ({} given)",
+                        name,
+                        values.len()
+                    ))
+                }
+            };
+            used[index] = true;
+            *expr = Expr::Value(values[index].clone());
+        }
+        ControlFlow::Continue(())
+    });
+    if let ControlFlow::Break(message) = flow {
+        return Err(invalid(message));
+    }
+    if let Some(unused) = used.iter().position(|&u| !u) {
+        return Err(invalid(format!("parameter ${} is not used by the query", unused + 1)));
+    }
+
+    Ok(statements.remove(0))
+}
+
+/// SQL literal for the parameter at 1-based position
+fn param_value(position: usize, param: &Literal) -> Result<Value, FfiResult> {
+    let value = match param.value_type {
+        0 => Value::Number(param.int_value.to_string(), false),
+        // Debug formatting keeps a fractional part or exponent, so the value stays a float
+        1 if param.float_value.is_finite() => Value::Number(format!("{:?}", param.float_value), false),
+        1 => {
+            return Err(invalid(format!(
+                "parameter ${} is {}, which has no SQL literal",
+                position, param.float_value
+            )))
+        }
+        2 => match unsafe { param.string_value.as_str() } {
+            Ok(s) => Value::SingleQuotedString(s.to_string()),
+            Err(_) => {
+                return Err(FfiResult::error(
+                    ERROR_INVALID_UTF8,
+                    &format!("Invalid UTF-8 in parameter ${}", position),
+                ))
+            }
+        },
+        3 => Value::Boolean(param.bool_value),
+        4 => Value::Null,
+        other => return Err(invalid(format!("parameter ${} has invalid literal type {}", position, other))),
+    };
+    Ok(value)
+}
+
+fn invalid(message: String) -> FfiResult {
+    FfiResult::error(ERROR_INVALID_OPERATION, &format!("QueryParams: {}", message))
+}
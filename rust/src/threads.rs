@@ -0,0 +1,25 @@
+use crate::{catch_panic, write_error, ERROR_PANIC};
+use polars_core::POOL;
+use std::os::raw::{c_char, c_int};
+
+/// Start the Polars thread pool if needed and return its size
+/// Polars sizes the pool from POLARS_MAX_THREADS when it first starts; the Go side sets
+/// that variable itself, since changing the environment from Rust while Go threads run
+/// is unsound and would leave Go's copy of it stale. Returns 0 if starting the pool panics.
+#[no_mangle]
+pub extern "C" fn thread_pool_init(
+    error_code: *mut c_int,
+    error_message: *mut *mut c_char,
+) -> usize {
+    let result = catch_panic(
+        || Ok(POOL.current_num_threads()),
+        |message| Err((ERROR_PANIC, message)),
+    );
+    match result {
+        Ok(size) => size,
+        Err((code, message)) => {
+            write_error(error_code, error_message, code, &message);
+            0
+        }
+    }
+}
@@ -92,6 +92,20 @@ pub struct AggregationArgs {
     pub ddof: u8, // Delta degrees of freedom (0=population, 1=sample)
 }
 
+/// Arguments for quantile aggregation
+#[repr(C)]
+pub struct QuantileArgs {
+    pub quantile: f64,      // Quantile in [0, 1]
+    pub interpolation: u8,  // 0=nearest, 1=lower, 2=higher, 3=midpoint, 4=linear
+}
+
+/// Arguments for skew/kurtosis aggregations
+#[repr(C)]
+pub struct MomentArgs {
+    pub fisher: bool, // Kurtosis only: subtract 3 so a normal distribution has 0.0
+    pub bias: bool,   // If false, correct for statistical bias
+}
+
 /// Arguments for count operations
 #[repr(C)]
 pub struct CountArgs {
@@ -106,10 +120,43 @@ pub struct CastArgs {
     pub wrap_numerical: bool, // If true, wrap overflowing numeric values instead of marking invalid
 }
 
+/// Arguments for map_batches: a Go function applied to whole columns
+#[repr(C)]
+pub struct MapBatchesArgs {
+    pub udf: usize,       // Go cgo.Handle of the function, passed back to the UDF callback
+    pub return_type: u32, // Output data type (bit-packed encoding)
+}
+
+/// Arguments for rolling window operations
+/// An empty `by` selects a fixed window of `window_size` rows; otherwise the
+/// window spans `duration` over the index column named by `by`
+#[repr(C)]
+pub struct RollingArgs {
+    pub window_size: usize, // Window length in rows (fixed windows only)
+    pub min_periods: usize, // Minimum number of non-null values required for a result
+    pub center: bool,       // Whether to center the window labels (fixed windows only)
+    pub by: RawStr,         // Index column for duration-based windows (empty = fixed window)
+    pub duration: RawStr,   // Window duration such as "7d" (duration-based windows only)
+    pub quantile: f64,      // Quantile in [0, 1] (rolling quantile only)
+    pub ddof: u8,           // Delta degrees of freedom (rolling std only)
+}
+
+/// Arguments for cumulative operations
+#[repr(C)]
+pub struct CumArgs {
+    pub reverse: bool, // Accumulate from the last row towards the first
+}
+
+/// Arguments for diff and pct_change operations
+#[repr(C)]
+pub struct DiffArgs {
+    pub n: i64, // Number of rows to shift when computing the difference
+}
+
 /// Centralized literal abstraction - C-compatible struct for various literal values
 #[repr(C)]
 pub struct Literal {
-    pub value_type: u8, // 0=int, 1=float, 2=string, 3=bool
+    pub value_type: u8, // 0=int, 1=float, 2=string, 3=bool, 4=null
     pub int_value: i64,
     pub float_value: f64,
     pub string_value: RawStr,
@@ -130,11 +177,38 @@ impl Literal {
                 }
             }
             3 => Ok(lit(self.bool_value)), // bool
+            4 => Ok(lit(Null {})),         // null
             _ => Err("Invalid literal type"),
         }
     }
 }
 
+/// Encode a Polars DataType with the bit-packed encoding of decode_data_type
+/// Returns None for types the Go side has no DataType for
+pub fn encode_data_type(dtype: &DataType) -> Option<u32> {
+    let encoded = match dtype {
+        DataType::Int8 => 0x0000_0001,
+        DataType::Int16 => 0x0000_0002,
+        DataType::Int32 => 0x0000_0003,
+        DataType::Int64 => 0x0000_0004,
+        DataType::UInt8 => 0x0000_0005,
+        DataType::UInt16 => 0x0000_0006,
+        DataType::UInt32 => 0x0000_0007,
+        DataType::UInt64 => 0x0000_0008,
+        DataType::Float32 => 0x0001_0001,
+        DataType::Float64 => 0x0001_0002,
+        DataType::String => 0x0002_0001,
+        DataType::Date => 0x0003_0001,
+        DataType::Time => 0x0003_0002,
+        DataType::Datetime(TimeUnit::Nanoseconds, _) => 0x0003_0003,
+        DataType::Datetime(TimeUnit::Microseconds, _) => 0x0003_0004,
+        DataType::Datetime(TimeUnit::Milliseconds, _) => 0x0003_0005,
+        DataType::Boolean => 0x0004_0001,
+        _ => return None,
+    };
+    Some(encoded)
+}
+
 /// Decode bit-packed data type from u32 to Polars DataType
 pub fn decode_data_type(encoded: u32) -> Result<DataType, FfiResult> {
     // Extract type family (high 16 bits) and variant (low 16 bits)
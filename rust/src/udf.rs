@@ -0,0 +1,290 @@
+use crate::expr::unary_expr_op;
+use crate::{
+    catch_panic, decode_data_type, encode_data_type, polars_error_code, ExecutionContext, FfiResult,
+    MapBatchesArgs, RawStr, ERROR_NULL_ARGS, ERROR_PANIC,
+};
+use polars::prelude::*;
+use std::os::raw::{c_char, c_int, c_void};
+use std::ptr;
+use std::slice;
+use std::sync::OnceLock;
+
+/// Column data passed between Rust and a Go UDF
+/// The buffers are borrowed for one callback; the receiver copies what it keeps
+#[repr(C)]
+pub struct SeriesView {
+    pub name: RawStr,
+    pub dtype: u32,             // Bit-packed DataType
+    pub len: usize,             // Number of rows
+    pub values: *const c_void,  // len fixed-width values (Boolean: one byte each), or len+1 i64 String offsets
+    pub data: *const u8,        // String bytes, indexed by the offsets (String only)
+    pub validity: *const u8,    // Arrow validity bitmap, bit set = row is valid; null when no row is null
+    pub validity_offset: usize, // Bit index of the first row in validity
+}
+
+/// Result slot a Go UDF fills through udf_output_set or udf_output_error
+#[derive(Default)]
+pub struct UdfOutput {
+    series: Option<Series>,
+    error: Option<String>,
+}
+
+/// Go function that runs the UDF behind `udf` on `input`; returns 0 on success
+pub type UdfCallback =
+    extern "C" fn(udf: usize, input: *const SeriesView, output: *mut UdfOutput) -> c_int;
+
+static UDF_CALLBACK: OnceLock<UdfCallback> = OnceLock::new();
+
+/// Install the Go function that runs UDFs; called once when the Go package initializes
+#[no_mangle]
+pub extern "C" fn register_udf_callback(callback: UdfCallback) {
+    let _ = UDF_CALLBACK.set(callback);
+}
+
+/// Store a UDF's result, copying it out of Go memory
+/// Returns 0, or an error code with the reason recorded in output
+#[no_mangle]
+pub extern "C" fn udf_output_set(output: *mut UdfOutput, view: *const SeriesView) -> c_int {
+    if output.is_null() || view.is_null() {
+        return ERROR_NULL_ARGS;
+    }
+    let output = unsafe { &mut *output };
+    catch_panic(
+        || match unsafe { series_from_view(&*view) } {
+            Ok(series) => {
+                output.series = Some(series);
+                0
+            }
+            Err(e) => {
+                output.error = Some(e.to_string());
+                polars_error_code(&e)
+            }
+        },
+        |message| {
+            output.error = Some(message);
+            ERROR_PANIC
+        },
+    )
+}
+
+/// Record the error a UDF returned
+#[no_mangle]
+pub extern "C" fn udf_output_error(output: *mut UdfOutput, message: RawStr) {
+    if output.is_null() {
+        return;
+    }
+    let message = unsafe { message.as_str() }.unwrap_or("UDF failed with a non-UTF-8 message");
+    unsafe { &mut *output }.error = Some(message.to_string());
+}
+
+/// MapBatches operation - applies a Go function to whole columns of the top expression
+pub fn expr_map_batches(ctx: &ExecutionContext) -> FfiResult {
+    let args = unsafe { &*(ctx.operation_args as *const MapBatchesArgs) };
+
+    let return_type = match decode_data_type(args.return_type) {
+        Ok(dt) => dt,
+        Err(err) => return err,
+    };
+    let udf = args.udf;
+
+    unary_expr_op(ctx, "map_batches", move |expr| {
+        let output_type = return_type.clone();
+        expr.map(
+            move |column: Column| call_udf(udf, &return_type, column),
+            move |_: &Schema, field: &Field| Ok(Field::new(field.name().clone(), output_type.clone())),
+        )
+    })
+}
+
+/// Run one batch through the Go UDF and cast the result to the declared type
+fn call_udf(udf: usize, return_type: &DataType, column: Column) -> PolarsResult<Column> {
+    let callback = *UDF_CALLBACK
+        .get()
+        .ok_or_else(|| polars_err!(ComputeError: "MapBatches: no Go callback registered"))?;
+
+    // One chunk, so every buffer Go sees is contiguous
+    let series = column.as_materialized_series().rechunk();
+    let input = InputView::new(&series)?;
+
+    let mut output = UdfOutput::default();
+    if callback(udf, &input.view, &mut output) != 0 {
+        let message = output.error.unwrap_or_else(|| "Go function failed".to_string());
+        polars_bail!(ComputeError: "MapBatches: {}", message);
+    }
+    let result = output
+        .series
+        .ok_or_else(|| polars_err!(ComputeError: "MapBatches: Go function returned no series"))?;
+
+    let result = if result.dtype() == return_type {
+        result
+    } else {
+        result.strict_cast(return_type)?
+    };
+    Ok(result.with_name(series.name().clone()).into_column())
+}
+
+/// SeriesView of a single-chunk series, with the buffers built for types whose
+/// Arrow layout Go cannot read directly (bit-packed booleans, string views)
+struct InputView {
+    view: SeriesView,
+    _bytes: Vec<u8>,    // Boolean values or String data
+    _offsets: Vec<i64>, // String offsets
+}
+
+impl InputView {
+    /// Numeric values and the validity bitmap are borrowed from series without copying
+    fn new(series: &Series) -> PolarsResult<Self> {
+        let dtype = match encode_data_type(series.dtype()) {
+            Some(encoded) if !series.dtype().is_temporal() => encoded,
+            _ => polars_bail!(
+                InvalidOperation: "MapBatches: unsupported input type {}; cast the column first",
+                series.dtype()
+            ),
+        };
+
+        let mut bytes = Vec::new();
+        let mut offsets = Vec::new();
+        let values = match series.dtype() {
+            DataType::Int8 => primitive_values(series.i8()?),
+            DataType::Int16 => primitive_values(series.i16()?),
+            DataType::Int32 => primitive_values(series.i32()?),
+            DataType::Int64 => primitive_values(series.i64()?),
+            DataType::UInt8 => primitive_values(series.u8()?),
+            DataType::UInt16 => primitive_values(series.u16()?),
+            DataType::UInt32 => primitive_values(series.u32()?),
+            DataType::UInt64 => primitive_values(series.u64()?),
+            DataType::Float32 => primitive_values(series.f32()?),
+            DataType::Float64 => primitive_values(series.f64()?),
+            DataType::Boolean => {
+                bytes = series.bool()?.iter().map(|v| v.unwrap_or(false) as u8).collect();
+                buffer_ptr(&bytes)
+            }
+            _ => {
+                // String
+                offsets.reserve(series.len() + 1);
+                offsets.push(0);
+                for value in series.str()?.iter() {
+                    bytes.extend_from_slice(value.unwrap_or("").as_bytes());
+                    offsets.push(bytes.len() as i64);
+                }
+                buffer_ptr(&offsets)
+            }
+        };
+
+        let (validity, validity_offset) =
+            match series.chunks().first().and_then(|array| array.validity()) {
+                Some(bitmap) if bitmap.unset_bits() > 0 => {
+                    let (bits, offset, _) = bitmap.as_slice();
+                    (bits.as_ptr(), offset)
+                }
+                _ => (ptr::null(), 0),
+            };
+
+        let name = series.name().as_str();
+        let view = SeriesView {
+            name: RawStr { data: name.as_ptr() as *const c_char, len: name.len() },
+            dtype,
+            len: series.len(),
+            values,
+            data: if offsets.is_empty() { ptr::null() } else { buffer_ptr(&bytes).cast() },
+            validity,
+            validity_offset,
+        };
+        Ok(Self { view, _bytes: bytes, _offsets: offsets })
+    }
+}
+
+/// Pointer to the values of a single-chunk numeric array
+fn primitive_values<T: PolarsNumericType>(ca: &ChunkedArray<T>) -> *const c_void {
+    ca.downcast_iter().next().map_or(ptr::null(), |array| buffer_ptr(array.values()))
+}
+
+/// Pointer to a buffer, or null when it is empty: an empty Vec's dangling pointer
+/// would trip Go's checkptr
+fn buffer_ptr<T>(buffer: &[T]) -> *const c_void {
+    if buffer.is_empty() {
+        ptr::null()
+    } else {
+        buffer.as_ptr().cast()
+    }
+}
+
+/// Build a Series by copying a view of Go memory
+unsafe fn series_from_view(view: &SeriesView) -> PolarsResult<Series> {
+    let name: PlSmallStr = view
+        .name
+        .as_str()
+        .map_err(|_| polars_err!(InvalidOperation: "series name is not valid UTF-8"))?
+        .into();
+    let dtype = decode_data_type(view.dtype)
+        .map_err(|_| polars_err!(InvalidOperation: "unknown series type {}", view.dtype))?;
+
+    let series = match dtype {
+        DataType::Int8 => numeric_series::<Int8Type>(name, view),
+        DataType::Int16 => numeric_series::<Int16Type>(name, view),
+        DataType::Int32 => numeric_series::<Int32Type>(name, view),
+        DataType::Int64 => numeric_series::<Int64Type>(name, view),
+        DataType::UInt8 => numeric_series::<UInt8Type>(name, view),
+        DataType::UInt16 => numeric_series::<UInt16Type>(name, view),
+        DataType::UInt32 => numeric_series::<UInt32Type>(name, view),
+        DataType::UInt64 => numeric_series::<UInt64Type>(name, view),
+        DataType::Float32 => numeric_series::<Float32Type>(name, view),
+        DataType::Float64 => numeric_series::<Float64Type>(name, view),
+        DataType::Boolean => {
+            let values = view_slice::<u8>(view.values, view.len);
+            let values = values.iter().enumerate().map(|(i, v)| is_valid(view, i).then_some(*v != 0));
+            BooleanChunked::from_iter_options(name, values).into_series()
+        }
+        DataType::String => {
+            let offsets = view_slice::<i64>(view.values, view.len + 1);
+            let data_len = offsets.last().map_or(0, |&end| end as usize);
+            let data = view_slice::<u8>(view.data.cast(), data_len);
+            let mut strings = Vec::with_capacity(view.len);
+            for i in 0..view.len {
+                if !is_valid(view, i) {
+                    strings.push(None);
+                    continue;
+                }
+                let bytes = &data[offsets[i] as usize..offsets[i + 1] as usize];
+                let value = std::str::from_utf8(bytes)
+                    .map_err(|_| polars_err!(InvalidOperation: "string at row {} is not valid UTF-8", i))?;
+                strings.push(Some(value));
+            }
+            StringChunked::from_iter_options(name, strings.into_iter()).into_series()
+        }
+        dtype => polars_bail!(InvalidOperation: "unsupported series type {}", dtype),
+    };
+    Ok(series)
+}
+
+/// Copy a numeric view into a Series, keeping its nulls
+unsafe fn numeric_series<T>(name: PlSmallStr, view: &SeriesView) -> Series
+where
+    T: PolarsNumericType,
+    ChunkedArray<T>: IntoSeries,
+{
+    let values = view_slice::<T::Native>(view.values, view.len);
+    if view.validity.is_null() {
+        return ChunkedArray::<T>::from_slice(name, values).into_series();
+    }
+    let values = values.iter().enumerate().map(|(i, v)| is_valid(view, i).then_some(*v));
+    ChunkedArray::<T>::from_iter_options(name, values).into_series()
+}
+
+/// Borrow len values of T from a view buffer; null buffers are empty
+unsafe fn view_slice<'a, T>(values: *const c_void, len: usize) -> &'a [T] {
+    if values.is_null() || len == 0 {
+        &[]
+    } else {
+        slice::from_raw_parts(values.cast(), len)
+    }
+}
+
+/// Whether row i of a view is valid according to its bitmap
+fn is_valid(view: &SeriesView, i: usize) -> bool {
+    if view.validity.is_null() {
+        return true;
+    }
+    let bit = view.validity_offset + i;
+    unsafe { *view.validity.add(bit / 8) & (1 << (bit % 8)) != 0 }
+}
@@ -98,10 +98,33 @@ fn test_read_csv_args() {
         len: path_bytes.len() - 1,
     };
 
+    let empty = RawStr {
+        data: std::ptr::null(),
+        len: 0,
+    };
+
     let args = ReadCsvArgs {
         path: raw_str,
         has_header: true,
         with_glob: false,
+        separator: 0,
+        quote_char: 0,
+        comment_prefix: empty,
+        skip_rows: 0,
+        n_rows: 0,
+        schema_overrides: std::ptr::null(),
+        schema_override_count: 0,
+        null_values: std::ptr::null(),
+        null_value_count: 0,
+        infer_schema_length: 0,
+        try_parse_dates: false,
+        ignore_errors: false,
+        encoding: CSV_ENCODING_UTF8,
+        low_memory: false,
+        row_index_name: empty,
+        row_index_offset: 0,
+        cloud: std::ptr::null(),
+        include_file_paths: empty,
     };
 
     // Verify we can read the path
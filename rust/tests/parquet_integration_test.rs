@@ -1,6 +1,6 @@
 use firn::{
     dispatch_read_parquet, ExecutionContext, ReadParquetArgs, RawStr, FfiResult,
-    PolarsHandle, ContextType, ERROR_POLARS_OPERATION,
+    PolarsHandle, ContextType, ERROR_POLARS_OPERATION, release_dataframe,
 };
 use std::ffi::CString;
 use std::ptr;
@@ -44,6 +44,19 @@ fn test_parquet_basic_read() {
         n_rows: 5, // Limit to 5 rows for testing
         parallel: true,
         with_glob: false,
+        hive_partitioning: false,
+        hive_schema: ptr::null(),
+        hive_schema_count: 0,
+        try_parse_hive_dates: false,
+        cloud: ptr::null(),
+        row_index_name: RawStr { data: ptr::null(), len: 0 },
+        row_index_offset: 0,
+        include_file_paths: RawStr { data: ptr::null(), len: 0 },
+        low_memory: false,
+        use_statistics: true,
+        rechunk: false,
+        predicate_ops: ptr::null(),
+        predicate_count: 0,
     };
     
     let context = ExecutionContext {
@@ -60,9 +73,7 @@ fn test_parquet_basic_read() {
     
     // Clean up
     if result.polars_handle.handle != 0 {
-        unsafe {
-            let _ = Box::from_raw(result.polars_handle.handle as *mut polars::prelude::DataFrame);
-        }
+        release_dataframe(result.polars_handle.handle);
     }
 }
 
@@ -80,6 +91,19 @@ fn test_parquet_column_selection() {
         n_rows: 3,
         parallel: true,
         with_glob: false,
+        hive_partitioning: false,
+        hive_schema: ptr::null(),
+        hive_schema_count: 0,
+        try_parse_hive_dates: false,
+        cloud: ptr::null(),
+        row_index_name: RawStr { data: ptr::null(), len: 0 },
+        row_index_offset: 0,
+        include_file_paths: RawStr { data: ptr::null(), len: 0 },
+        low_memory: false,
+        use_statistics: true,
+        rechunk: false,
+        predicate_ops: ptr::null(),
+        predicate_count: 0,
     };
     
     let context = ExecutionContext {
@@ -95,9 +119,7 @@ fn test_parquet_column_selection() {
     
     // Clean up
     if result.polars_handle.handle != 0 {
-        unsafe {
-            let _ = Box::from_raw(result.polars_handle.handle as *mut polars::prelude::DataFrame);
-        }
+        release_dataframe(result.polars_handle.handle);
     }
 }
 
@@ -113,6 +135,19 @@ fn test_parquet_nonexistent_file() {
         n_rows: 0,
         parallel: true,
         with_glob: false,
+        hive_partitioning: false,
+        hive_schema: ptr::null(),
+        hive_schema_count: 0,
+        try_parse_hive_dates: false,
+        cloud: ptr::null(),
+        row_index_name: RawStr { data: ptr::null(), len: 0 },
+        row_index_offset: 0,
+        include_file_paths: RawStr { data: ptr::null(), len: 0 },
+        low_memory: false,
+        use_statistics: true,
+        rechunk: false,
+        predicate_ops: ptr::null(),
+        predicate_count: 0,
     };
     
     let context = ExecutionContext {
@@ -149,6 +184,19 @@ fn test_parquet_scan_args_optimization() {
         n_rows: 1, // Very small limit to test optimization
         parallel: false, // Test non-parallel path
         with_glob: false,
+        hive_partitioning: false,
+        hive_schema: ptr::null(),
+        hive_schema_count: 0,
+        try_parse_hive_dates: false,
+        cloud: ptr::null(),
+        row_index_name: RawStr { data: ptr::null(), len: 0 },
+        row_index_offset: 0,
+        include_file_paths: RawStr { data: ptr::null(), len: 0 },
+        low_memory: false,
+        use_statistics: true,
+        rechunk: false,
+        predicate_ops: ptr::null(),
+        predicate_count: 0,
     };
     
     let context = ExecutionContext {
@@ -167,9 +215,9 @@ fn test_parquet_scan_args_optimization() {
         unsafe {
             let df = &*(result.polars_handle.handle as *const polars::prelude::DataFrame);
             assert_eq!(df.height(), 1);
-            
-            // Clean up
-            let _ = Box::from_raw(result.polars_handle.handle as *mut polars::prelude::DataFrame);
         }
+
+        // Clean up
+        release_dataframe(result.polars_handle.handle);
     }
 }